@@ -29,13 +29,26 @@ import (
 
 	"github.com/redpanda-data/connect/v4/public/schema"
 
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/counter_rules"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/counters"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/goals"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/grants"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/logs"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/segments"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/stat_table"
 
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika/apps"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika/stat_table"
 
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/calls"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/cdp"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/counter_rules"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/expenses"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/goals"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/offline_conversions"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/segments"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/user_params"
+
 	_ "embed"
 )
 
@@ -19,6 +19,7 @@ import (
 	// _ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/bloblang"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika"
 )
 
 var (
@@ -0,0 +1,33 @@
+package configs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/io"
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika"
+)
+
+func TestExampleConfigs(t *testing.T) {
+	files, err := filepath.Glob("yandex/*/*/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			conf, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			assert.NoError(t, service.NewStreamBuilder().SetYAML(string(conf)))
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	//go:embed data/metrika.json
+	metrikaData []byte
+	//go:embed data/appmetrika.json
+	appMetrikaData []byte
+)
+
+var (
+	// Metrika is a catalog of the Yandex.Metrika Reporting API fields.
+	Metrika = mustLoad(metrikaData)
+	// AppMetrika is a catalog of the Yandex.AppMetrika Reporting API fields.
+	AppMetrika = mustLoad(appMetrikaData)
+)
+
+// Fields is a list of metrics and dimensions of a namespace, e.g. `s` for `ym:s:`, without the namespace prefix.
+// Metrics are mapped to their value types.
+type Fields struct {
+	Metrics    map[string]string `json:"metrics"`
+	Dimensions []string          `json:"dimensions"`
+}
+
+// Catalog is a sorted list of known metrics and dimensions with the namespace prefix.
+// Parametrized fields are stored with placeholders, e.g. `ym:s:goal<goal_id>reaches`.
+type Catalog struct {
+	metrics    []string
+	dimensions []string
+	types      map[string]string
+}
+
+// normalizers replace parameter values in field names with placeholders.
+var normalizers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{
+		re:   regexp.MustCompile(`goal[0-9]+`),
+		repl: "goal<goal_id>",
+	},
+	{
+		re:   regexp.MustCompile(`datePeriod(all|auto|dekaminute|minute|hours|hour|day|week|month|quarter|year)`),
+		repl: "datePeriod<group>",
+	},
+	{
+		re:   regexp.MustCompile(`^(ym:[a-z0-9]+:)(cross_device_last_yandex_direct_click|cross_device_last_significant|cross_device_first|cross_device_last|last_yandex_direct_click|lastsign|automatic|first|last)([A-Z])`),
+		repl: "${1}<attribution>${3}",
+	},
+	{
+		re:   regexp.MustCompile(`(RUB|USD|EUR|UAH|BYN|KZT|TRY|GBP|CHF|CNY|YND)([A-Z][a-z])`),
+		repl: "<currency>${2}",
+	},
+}
+
+func mustLoad(data []byte) *Catalog {
+	var namespaces map[string]Fields
+
+	if err := json.Unmarshal(data, &namespaces); err != nil {
+		panic(err)
+	}
+
+	c := &Catalog{
+		types: make(map[string]string),
+	}
+
+	for ns, fields := range namespaces {
+		for m, t := range fields.Metrics {
+			if !slices.Contains(types, t) {
+				panic(fmt.Sprintf("metric %q has unknown type %q", m, t))
+			}
+
+			c.metrics = append(c.metrics, "ym:"+ns+":"+m)
+			c.types["ym:"+ns+":"+m] = t
+		}
+
+		for _, d := range fields.Dimensions {
+			c.dimensions = append(c.dimensions, "ym:"+ns+":"+d)
+		}
+	}
+
+	slices.Sort(c.metrics)
+	slices.Sort(c.dimensions)
+
+	return c
+}
+
+// Normalize replaces goal IDs, groups, attributions and currencies in the field name with placeholders.
+func Normalize(name string) string {
+	for _, n := range normalizers {
+		name = n.re.ReplaceAllString(name, n.repl)
+	}
+
+	return name
+}
+
+// HasMetric reports whether the metric is in the catalog.
+func (c *Catalog) HasMetric(name string) bool {
+	return contains(c.metrics, name)
+}
+
+// HasDimension reports whether the dimension is in the catalog.
+func (c *Catalog) HasDimension(name string) bool {
+	return contains(c.dimensions, name)
+}
+
+func contains(names []string, name string) bool {
+	_, ok := slices.BinarySearch(names, name)
+	if ok {
+		return true
+	}
+
+	_, ok = slices.BinarySearch(names, Normalize(name))
+
+	return ok
+}
+
+// LintRule returns Bloblang statements which check the `metrics` and `dimensions` config fields
+// and store the lint messages in the `$catalog_lints` variable.
+// Unknown fields are reported unless the `catalog_validation` config field is false.
+func (c *Catalog) LintRule() string {
+	normalize := "n"
+	for _, n := range normalizers {
+		normalize += fmt.Sprintf(".re_replace_all(%s, %s)", strconv.Quote(n.re.String()), strconv.Quote(n.repl))
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "let catalog_metrics = %s\n", quoteList(c.metrics))
+	fmt.Fprintf(&sb, "let catalog_dimensions = %s\n", quoteList(c.dimensions))
+	sb.WriteString("let config_metrics = this.metrics.or([]).map_each(n -> n.string())\n")
+	sb.WriteString("let config_dimensions = this.dimensions.or([]).map_each(n -> n.string())\n")
+	sb.WriteString("let catalog_lints = if this.catalog_validation.or(true) {\n")
+	sb.WriteString("  [\n")
+	fmt.Fprintf(&sb, "    $config_metrics.filter(n -> !$catalog_metrics.contains(n) && !$catalog_metrics.contains(%s)).map_each(n -> \"unknown metric %%q\".format(n)),\n", normalize)
+	fmt.Fprintf(&sb, "    $config_dimensions.filter(n -> !$catalog_dimensions.contains(n) && !$catalog_dimensions.contains(%s)).map_each(n -> \"unknown dimension %%q\".format(n)),\n", normalize)
+	sb.WriteString("  ].flatten()\n")
+	sb.WriteString("} else {\n")
+	sb.WriteString("  []\n")
+	sb.WriteString("}\n")
+	sb.WriteString("let catalog_namespaces = [$config_metrics, $config_dimensions].flatten().map_each(n -> n.re_replace_all(\"^(ym:[a-z0-9]+):.*$\", \"${1}\")).unique()\n")
+	sb.WriteString("let catalog_lints = if $catalog_namespaces.length() > 1 {\n")
+	sb.WriteString("  $catalog_lints.append(\"metrics and dimensions must be in the same namespace, got %s\".format($catalog_namespaces.join(\", \")))\n")
+	sb.WriteString("} else {\n")
+	sb.WriteString("  $catalog_lints\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+func quoteList(names []string) string {
+	quoted := make([]string, 0, len(names))
+
+	for _, n := range names {
+		quoted = append(quoted, strconv.Quote(n))
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
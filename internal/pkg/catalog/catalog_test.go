@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "ym:s:visits", expected: "ym:s:visits"},
+		{name: "ym:s:goal123reaches", expected: "ym:s:goal<goal_id>reaches"},
+		{name: "ym:s:datePeriodday", expected: "ym:s:datePeriod<group>"},
+		{name: "ym:s:datePeriodhoursName", expected: "ym:s:datePeriod<group>Name"},
+		{name: "ym:s:lastsignTrafficSource", expected: "ym:s:<attribution>TrafficSource"},
+		{name: "ym:s:cross_device_lastTrafficSource", expected: "ym:s:<attribution>TrafficSource"},
+		{name: "ym:s:ecommerceRUBConvertedRevenue", expected: "ym:s:ecommerce<currency>ConvertedRevenue"},
+		{name: "ym:s:goal1USDConvertedRevenue", expected: "ym:s:goal<goal_id><currency>ConvertedRevenue"},
+		{name: "ym:ad:RUBAdCost", expected: "ym:ad:<currency>AdCost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalize(tt.name))
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	assert.True(t, Metrika.HasMetric("ym:s:visits"))
+	assert.True(t, Metrika.HasMetric("ym:s:goal123reaches"))
+	assert.True(t, Metrika.HasMetric("ym:s:goal<goal_id>reaches"))
+	assert.True(t, Metrika.HasMetric("ym:ad:RUBAdCost"))
+	assert.True(t, Metrika.HasDimension("ym:s:lastTrafficSource"))
+	assert.True(t, Metrika.HasDimension("ym:s:firstVisitDate"))
+	assert.True(t, Metrika.HasDimension("ym:s:UTMSource"))
+	assert.True(t, Metrika.HasDimension("ym:pv:URLPath"))
+	assert.False(t, Metrika.HasMetric("ym:s:visit"))
+	assert.False(t, Metrika.HasMetric("ym:s:lastTrafficSource"))
+	assert.False(t, Metrika.HasDimension("ym:i:date"))
+
+	assert.True(t, AppMetrika.HasMetric("ym:ge:users"))
+	assert.True(t, AppMetrika.HasMetric("ym:i:installDevices"))
+	assert.True(t, AppMetrika.HasDimension("ym:i:date"))
+	assert.False(t, AppMetrika.HasMetric("ym:s:visits"))
+}
+
+func TestLintRule(t *testing.T) {
+	exec, err := bloblang.Parse(Metrika.LintRule() + "root = $catalog_lints")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		conf     map[string]any
+		expected []any
+	}{
+		{
+			name: "valid",
+			conf: map[string]any{
+				"metrics":    []any{"ym:s:visits", "ym:s:goal123reaches", "ym:s:goal<goal_id>visits"},
+				"dimensions": []any{"ym:s:lastsignTrafficSource", "ym:s:date"},
+			},
+			expected: []any{},
+		},
+		{
+			name: "unknown",
+			conf: map[string]any{
+				"metrics":            []any{"ym:s:visit"},
+				"dimensions":         []any{"ym:s:dates"},
+				"catalog_validation": true,
+			},
+			expected: []any{`unknown metric "ym:s:visit"`, `unknown dimension "ym:s:dates"`},
+		},
+		{
+			name: "unknown by default",
+			conf: map[string]any{
+				"metrics":    []any{"ym:s:visit"},
+				"dimensions": []any{"ym:s:dates"},
+			},
+			expected: []any{`unknown metric "ym:s:visit"`, `unknown dimension "ym:s:dates"`},
+		},
+		{
+			name: "unknown without validation",
+			conf: map[string]any{
+				"metrics":            []any{"ym:s:visit"},
+				"catalog_validation": false,
+			},
+			expected: []any{},
+		},
+		{
+			name: "mixed namespaces",
+			conf: map[string]any{
+				"metrics":    []any{"ym:s:visits"},
+				"dimensions": []any{"ym:pv:URLPath"},
+			},
+			expected: []any{"metrics and dimensions must be in the same namespace, got ym:s, ym:pv"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := exec.Query(tt.conf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
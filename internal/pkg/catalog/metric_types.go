@@ -0,0 +1,44 @@
+package catalog
+
+import (
+	"math"
+)
+
+// Metric value types.
+const (
+	TypeInteger  = "integer"  // TypeInteger is a counter metric, e.g. visits or users.
+	TypeFloat    = "float"    // TypeFloat is a fractional metric, e.g. page depth.
+	TypePercent  = "percent"  // TypePercent is a percentage metric in the [0; 100] range.
+	TypeDuration = "duration" // TypeDuration is a duration metric in seconds.
+	TypeCurrency = "currency" // TypeCurrency is a money metric, e.g. revenue.
+)
+
+// types is a list of the metric value types of the catalog data.
+var types = []string{TypeInteger, TypeFloat, TypePercent, TypeDuration, TypeCurrency}
+
+// MetricType returns the value type of the metric from the catalog.
+// The second value is false if the metric is not in the catalog.
+func (c *Catalog) MetricType(name string) (string, bool) {
+	if t, ok := c.types[name]; ok {
+		return t, true
+	}
+
+	t, ok := c.types[Normalize(name)]
+
+	return t, ok
+}
+
+// MetricValue converts the metric value to its type:
+// integer metrics become int64 and null values stay nil.
+// Metrics missing from the catalog keep float values.
+func (c *Catalog) MetricValue(name string, v *float64) any {
+	if v == nil {
+		return nil
+	}
+
+	if t, _ := c.MetricType(name); t == TypeInteger {
+		return int64(math.Round(*v))
+	}
+
+	return *v
+}
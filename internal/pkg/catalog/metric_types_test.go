@@ -0,0 +1,58 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog_MetricType(t *testing.T) {
+	tests := []struct {
+		catalog  *Catalog
+		metric   string
+		expected string
+		known    bool
+	}{
+		{catalog: Metrika, metric: "ym:s:visits", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:s:users", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:pv:pageviews", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:s:goal123reaches", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:s:goal<goal_id>visits", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:s:goal123conversionRate", expected: TypePercent, known: true},
+		{catalog: Metrika, metric: "ym:s:goal123revenue", expected: TypeCurrency, known: true},
+		{catalog: Metrika, metric: "ym:s:bounceRate", expected: TypePercent, known: true},
+		{catalog: Metrika, metric: "ym:s:percentNewVisitors", expected: TypePercent, known: true},
+		{catalog: Metrika, metric: "ym:s:avgVisitDurationSeconds", expected: TypeDuration, known: true},
+		{catalog: Metrika, metric: "ym:s:avgDaysBetweenVisits", expected: TypeFloat, known: true},
+		{catalog: Metrika, metric: "ym:s:pageDepth", expected: TypeFloat, known: true},
+		{catalog: Metrika, metric: "ym:s:sumPageViews", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:s:offlineCalls", expected: TypeInteger, known: true},
+		{catalog: Metrika, metric: "ym:s:ecommerceRevenue", expected: TypeCurrency, known: true},
+		{catalog: Metrika, metric: "ym:ge:users", known: false},
+		{catalog: Metrika, metric: "ym:s:unknownMetric", known: false},
+		{catalog: AppMetrika, metric: "ym:ge:users", expected: TypeInteger, known: true},
+		{catalog: AppMetrika, metric: "ym:ge:retentionDay7", expected: TypePercent, known: true},
+		{catalog: AppMetrika, metric: "ym:i:installDevices", expected: TypeInteger, known: true},
+		{catalog: AppMetrika, metric: "ym:ce:devices", expected: TypeInteger, known: true},
+		{catalog: AppMetrika, metric: "ym:r:arpu", expected: TypeCurrency, known: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			typ, ok := tt.catalog.MetricType(tt.metric)
+			assert.Equal(t, tt.known, ok)
+			assert.Equal(t, tt.expected, typ)
+		})
+	}
+}
+
+func TestCatalog_MetricValue(t *testing.T) {
+	visits := 1234.0
+	bounceRate := 12.5
+	unknown := 3.0
+
+	assert.Equal(t, int64(1234), Metrika.MetricValue("ym:s:visits", &visits))
+	assert.Equal(t, 12.5, Metrika.MetricValue("ym:s:bounceRate", &bounceRate))
+	assert.Equal(t, 3.0, Metrika.MetricValue("ym:s:unknownMetric", &unknown))
+	assert.Nil(t, Metrika.MetricValue("ym:s:visits", nil))
+}
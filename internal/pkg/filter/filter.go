@@ -0,0 +1,224 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Node operators.
+const (
+	OpEqual         = "eq"
+	OpNotEqual      = "ne"
+	OpGreater       = "gt"
+	OpGreaterEqual  = "ge"
+	OpLess          = "lt"
+	OpLessEqual     = "le"
+	OpContains      = "contains"
+	OpNotContains   = "not_contains"
+	OpRegex         = "regex"
+	OpNotRegex      = "not_regex"
+	OpStartsWith    = "starts_with"
+	OpNotStartsWith = "not_starts_with"
+	OpIn            = "in"
+	OpNotIn         = "not_in"
+)
+
+// operators maps node operators to the Metrika filter syntax.
+var operators = map[string]string{
+	OpEqual:         "==",
+	OpNotEqual:      "!=",
+	OpGreater:       ">",
+	OpGreaterEqual:  ">=",
+	OpLess:          "<",
+	OpLessEqual:     "<=",
+	OpContains:      "=@",
+	OpNotContains:   "!@",
+	OpRegex:         "=~",
+	OpNotRegex:      "!~",
+	OpStartsWith:    "=*",
+	OpNotStartsWith: "!*",
+	OpIn:            "=.",
+	OpNotIn:         "!.",
+}
+
+var fieldRe = regexp.MustCompile(`^ym:[a-z0-9]+:[A-Za-z0-9_<>]+$`)
+
+// LintRule is a Bloblang lint mapping for the structured filter config field.
+const LintRule = `
+map filter_node {
+  root = if this.type() != "object" {
+    ["filter node must be an object"]
+  } else if this.keys().filter(k -> ["and", "or", "not", "field"].contains(k)).length() != 1 {
+    ["filter node must have exactly one of the and, or, not or field keys"]
+  } else if (this.and | this.or | null).type() == "array" && (this.and | this.or).length() > 0 {
+    (this.and | this.or).map_each(n -> n.apply("filter_node")).flatten()
+  } else if this.exists("and") || this.exists("or") {
+    ["filter and/or node must be a non-empty array"]
+  } else if this.exists("not") {
+    this.not.apply("filter_node")
+  } else if !this.field.string().re_match("^ym:[a-z0-9]+:[A-Za-z0-9_<>]+$") {
+    ["filter field %q is not a valid Metrika field".format(this.field.string())]
+  } else if !["eq", "ne", "gt", "ge", "lt", "le", "contains", "not_contains", "regex", "not_regex", "starts_with", "not_starts_with", "in", "not_in"].contains(this.op) {
+    ["filter op %q is not supported".format(this.op.string())]
+  } else if ["in", "not_in"].contains(this.op) && (this.value.type() != "array" || this.value.length() == 0) {
+    ["filter value of the %s op must be a non-empty array".format(this.op)]
+  } else if !["in", "not_in"].contains(this.op) && !["string", "number"].contains(this.value.type()) {
+    ["filter value of the %s op must be a string or a number".format(this.op)]
+  } else {
+    []
+  }
+}
+
+root = this.apply("filter_node")
+`
+
+// Compile converts a structured filter into the Metrika filter syntax.
+//
+// A node is an object with exactly one of the keys:
+//   - and: a list of nodes joined with AND;
+//   - or: a list of nodes joined with OR;
+//   - not: a node to negate;
+//   - field: a condition with the op and value keys.
+func Compile(node any) (string, error) {
+	obj, ok := node.(map[string]any)
+	if !ok {
+		return "", errors.New("filter node must be an object")
+	}
+
+	keys := 0
+
+	for _, k := range []string{"and", "or", "not", "field"} {
+		if _, ok := obj[k]; ok {
+			keys++
+		}
+	}
+
+	if keys != 1 {
+		return "", errors.New("filter node must have exactly one of the and, or, not or field keys")
+	}
+
+	if v, ok := obj["and"]; ok {
+		return compileGroup(v, "AND")
+	}
+
+	if v, ok := obj["or"]; ok {
+		return compileGroup(v, "OR")
+	}
+
+	if v, ok := obj["not"]; ok {
+		expr, err := Compile(v)
+		if err != nil {
+			return "", err
+		}
+
+		return "NOT(" + expr + ")", nil
+	}
+
+	return compileCondition(obj)
+}
+
+func compileGroup(v any, op string) (string, error) {
+	nodes, ok := v.([]any)
+	if !ok || len(nodes) == 0 {
+		return "", errors.New("filter and/or node must be a non-empty array")
+	}
+
+	exprs := make([]string, 0, len(nodes))
+
+	for _, n := range nodes {
+		expr, err := Compile(n)
+		if err != nil {
+			return "", err
+		}
+
+		if isGroup(n) {
+			expr = "(" + expr + ")"
+		}
+
+		exprs = append(exprs, expr)
+	}
+
+	return strings.Join(exprs, " "+op+" "), nil
+}
+
+// isGroup reports whether the node is an and/or node which needs parentheses.
+func isGroup(node any) bool {
+	obj, _ := node.(map[string]any)
+	_, and := obj["and"]
+	_, or := obj["or"]
+
+	return and || or
+}
+
+func compileCondition(obj map[string]any) (string, error) {
+	field, ok := obj["field"].(string)
+	if !ok || !fieldRe.MatchString(field) {
+		return "", fmt.Errorf("filter field %v is not a valid Metrika field", obj["field"])
+	}
+
+	name, _ := obj["op"].(string)
+
+	op, ok := operators[name]
+	if !ok {
+		return "", fmt.Errorf("filter op %v is not supported", obj["op"])
+	}
+
+	if name == OpIn || name == OpNotIn {
+		values, ok := obj["value"].([]any)
+		if !ok || len(values) == 0 {
+			return "", fmt.Errorf("filter value of the %s op must be a non-empty array", name)
+		}
+
+		items := make([]string, 0, len(values))
+
+		for _, v := range values {
+			item, err := formatValue(v)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, item)
+		}
+
+		return field + op + "(" + strings.Join(items, ",") + ")", nil
+	}
+
+	value, err := formatValue(obj["value"])
+	if err != nil {
+		return "", err
+	}
+
+	return field + op + value, nil
+}
+
+// formatValue formats a scalar value. Strings are quoted and escaped, numbers are kept as is.
+func formatValue(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return Quote(t), nil
+	case int:
+		return strconv.Itoa(t), nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case uint64:
+		return strconv.FormatUint(t, 10), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case json.Number:
+		return t.String(), nil
+	default:
+		return "", fmt.Errorf("filter value %v must be a string or a number", v)
+	}
+}
+
+// Quote wraps a string in single quotes and escapes backslashes and quotes.
+func Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+
+	return "'" + s + "'"
+}
@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     any
+		expected string
+	}{
+		{
+			name:     "condition",
+			node:     map[string]any{"field": "ym:s:lastTrafficSource", "op": "eq", "value": "direct"},
+			expected: "ym:s:lastTrafficSource=='direct'",
+		},
+		{
+			name: "and",
+			node: map[string]any{"and": []any{
+				map[string]any{"field": "ym:s:lastTrafficSource", "op": "eq", "value": "direct"},
+				map[string]any{"field": "ym:s:regionCountry", "op": "eq", "value": "225"},
+			}},
+			expected: "ym:s:lastTrafficSource=='direct' AND ym:s:regionCountry=='225'",
+		},
+		{
+			name: "nested or",
+			node: map[string]any{"and": []any{
+				map[string]any{"field": "ym:s:pageViews", "op": "gt", "value": 1},
+				map[string]any{"or": []any{
+					map[string]any{"field": "ym:s:browser", "op": "starts_with", "value": "Chrome"},
+					map[string]any{"field": "ym:s:browser", "op": "contains", "value": "Safari"},
+				}},
+			}},
+			expected: "ym:s:pageViews>1 AND (ym:s:browser=*'Chrome' OR ym:s:browser=@'Safari')",
+		},
+		{
+			name:     "not",
+			node:     map[string]any{"not": map[string]any{"field": "ym:s:isRobot", "op": "eq", "value": "Yes"}},
+			expected: "NOT(ym:s:isRobot=='Yes')",
+		},
+		{
+			name:     "in list",
+			node:     map[string]any{"field": "ym:s:regionCountry", "op": "in", "value": []any{"225", 187, 149.5}},
+			expected: "ym:s:regionCountry=.('225',187,149.5)",
+		},
+		{
+			name:     "not in list",
+			node:     map[string]any{"field": "ym:s:lastTrafficSource", "op": "not_in", "value": []any{"ad", "internal"}},
+			expected: "ym:s:lastTrafficSource!.('ad','internal')",
+		},
+		{
+			name:     "escaping",
+			node:     map[string]any{"field": "ym:s:startURL", "op": "regex", "value": `it's\d+`},
+			expected: `ym:s:startURL=~'it\'s\\d+'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compile(tt.node)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		node any
+	}{
+		{name: "not an object", node: "ym:s:visits>1"},
+		{name: "no keys", node: map[string]any{}},
+		{name: "several keys", node: map[string]any{"and": []any{}, "field": "ym:s:browser"}},
+		{name: "empty and", node: map[string]any{"and": []any{}}},
+		{name: "invalid field", node: map[string]any{"field": "browser", "op": "eq", "value": "Chrome"}},
+		{name: "invalid op", node: map[string]any{"field": "ym:s:browser", "op": "==", "value": "Chrome"}},
+		{name: "scalar in list op", node: map[string]any{"field": "ym:s:browser", "op": "in", "value": "Chrome"}},
+		{name: "list in scalar op", node: map[string]any{"field": "ym:s:browser", "op": "eq", "value": []any{"Chrome"}}},
+		{name: "nested error", node: map[string]any{"not": map[string]any{"field": "ym:s:browser"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.node)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLintRule(t *testing.T) {
+	exec, err := bloblang.Parse(LintRule)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		node  any
+		lints int
+	}{
+		{
+			name: "valid",
+			node: map[string]any{"and": []any{
+				map[string]any{"field": "ym:s:lastTrafficSource", "op": "eq", "value": "direct"},
+				map[string]any{"not": map[string]any{"field": "ym:s:regionCountry", "op": "in", "value": []any{225, 187}}},
+			}},
+			lints: 0,
+		},
+		{
+			name:  "several keys",
+			node:  map[string]any{"or": []any{}, "field": "ym:s:browser"},
+			lints: 1,
+		},
+		{
+			name:  "empty or",
+			node:  map[string]any{"or": []any{}},
+			lints: 1,
+		},
+		{
+			name: "nested errors",
+			node: map[string]any{"and": []any{
+				map[string]any{"field": "browser", "op": "eq", "value": "Chrome"},
+				map[string]any{"field": "ym:s:browser", "op": "in", "value": "Chrome"},
+				map[string]any{"field": "ym:s:browser", "op": "like", "value": "Chrome"},
+			}},
+			lints: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := exec.Query(tt.node)
+			require.NoError(t, err)
+			assert.Len(t, res, tt.lints)
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package stattable
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// BatchOptions controls how a stat table response is converted to messages.
+type BatchOptions struct {
+	DimensionIDs string           // DimensionIDs is a dimension IDs mode.
+	Shape        string           // Shape is a report shape. The table shape is built by the caller from the wide rows.
+	Catalog      *catalog.Catalog // Catalog types the metric values if set: integer metrics are converted to int64.
+	Keys         utils.KeyMapper  // Keys converts field names to message keys, the snake strategy is used by default.
+}
+
+// Batch creates a service.MessageBatch from the Response.
+func (r *Response) Batch() (service.MessageBatch, error) {
+	return r.BatchWithOptions(BatchOptions{})
+}
+
+// BatchWithOptions creates a service.MessageBatch from the Response
+// using the given options.
+func (r *Response) BatchWithOptions(opts BatchOptions) (service.MessageBatch, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+
+	msgs := make(service.MessageBatch, 0, len(r.Data))
+
+	for _, e := range r.Data {
+		row := make(map[string]any)
+
+		for di, d := range e.Dimensions {
+			k := opts.Keys.Key(r.Query.Dimensions[di])
+
+			switch opts.DimensionIDs {
+			case DimensionIDsField:
+				row[k] = d.Name
+				row[k+"_id"] = dimensionID(d.Id)
+			case DimensionIDsObject:
+				row[k] = map[string]any{
+					"name": d.Name,
+					"id":   dimensionID(d.Id),
+				}
+			default:
+				row[k] = d.Name
+			}
+		}
+
+		metrics := make([]string, len(e.Metrics))
+
+		for mi, m := range e.Metrics {
+			k := opts.Keys.Key(r.Query.Metrics[mi])
+			row[k] = metricValue(r.Query.Metrics[mi], m, opts.Catalog)
+			metrics[mi] = k
+		}
+
+		for _, shaped := range shapeRows(opts.Shape, row, metrics) {
+			msg := service.NewMessage(nil)
+			msg.SetStructuredMut(shaped)
+			msg.MetaSetMut("row_type", "data")
+
+			if err := r.setMeta(msg, opts); err != nil {
+				return nil, err
+			}
+
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs, nil
+}
+
+// TotalsMessage creates a service.Message with the report totals.
+func (r *Response) TotalsMessage(opts BatchOptions) (*service.Message, error) {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(r.metricsMap(r.Totals, opts))
+	msg.MetaSetMut("row_type", "totals")
+
+	if err := r.setMeta(msg, opts); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// setMeta sets the query, pagination, sampling and data quality metadata.
+func (r *Response) setMeta(msg *service.Message, opts BatchOptions) error {
+	query, err := utils.StructToMap(r.Query)
+	if err != nil {
+		return err
+	}
+
+	msg.MetaSetMut("query", query)
+	msg.MetaSetMut("limit", r.Query.Limit)
+	msg.MetaSetMut("offset", r.Query.Offset)
+	msg.MetaSetMut("total", r.TotalRows)
+	msg.MetaSetMut("totals", r.metricsMap(r.Totals, opts))
+	msg.MetaSetMut("min", r.metricsMap(r.Min, opts))
+	msg.MetaSetMut("max", r.metricsMap(r.Max, opts))
+	msg.MetaSetMut("sampled", r.Sampled)
+	msg.MetaSetMut("sample_share", r.SampleShare)
+	msg.MetaSetMut("sample_size", r.SampleSize)
+	msg.MetaSetMut("sample_space", r.SampleSpace)
+	msg.MetaSetMut("data_lag", r.DataLag)
+	msg.MetaSetMut("contains_sensitive_data", r.ContainsSensitiveData)
+
+	if opts.Catalog != nil {
+		msg.MetaSetMut("metric_types", metricTypes(opts.Catalog, r.Query.Metrics, opts.Keys))
+	}
+
+	return nil
+}
+
+// metricsMap maps metric values to metric keys.
+func (r *Response) metricsMap(values []*float64, opts BatchOptions) map[string]any {
+	m := make(map[string]any, len(values))
+
+	for mi, v := range values {
+		k := opts.Keys.Key(r.Query.Metrics[mi])
+		m[k] = metricValue(r.Query.Metrics[mi], v, opts.Catalog)
+	}
+
+	return m
+}
+
+// metricValue converts the metric value to int64 for integer metrics of the catalog if it is set.
+// A null value is converted to nil.
+func metricValue(metric string, v *float64, c *catalog.Catalog) any {
+	if c != nil {
+		return c.MetricValue(metric, v)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return *v
+}
+
+// metricTypes maps metric types from the metrics catalog to metric keys.
+// Metrics missing from the catalog are skipped.
+func metricTypes(c *catalog.Catalog, metrics []string, keys utils.KeyMapper) map[string]any {
+	m := make(map[string]any, len(metrics))
+
+	for _, metric := range metrics {
+		if t, ok := c.MetricType(metric); ok {
+			m[keys.Key(metric)] = t
+		}
+	}
+
+	return m
+}
+
+// shapeRows converts a wide row to the rows of the given shape.
+func shapeRows(shape string, row map[string]any, metrics []string) []map[string]any {
+	if shape == ShapeLong {
+		return utils.LongRows(row, metrics)
+	}
+
+	return []map[string]any{row}
+}
+
+// dimensionID returns the dimension ID or nil if the dimension has no ID.
+func dimensionID(id string) any {
+	if len(id) == 0 {
+		return nil
+	}
+
+	return id
+}
+
+// Batch reads the CSV body row by row and creates a service.MessageBatch from it.
+// The CSV header contains localized column names, so the keys are taken from the query.
+// The body is closed when it is read.
+func (r *CSVResponse) Batch() (service.MessageBatch, error) {
+	return r.BatchWithOptions(BatchOptions{})
+}
+
+// BatchWithOptions reads the CSV body and creates a service.MessageBatch from it
+// using the given options. Dimension IDs are not available in the CSV format.
+func (r *CSVResponse) BatchWithOptions(opts BatchOptions) (service.MessageBatch, error) {
+	defer r.Body.Close()
+
+	reader := csv.NewReader(r.Body)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	// skip header
+	if _, err := reader.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	query, err := utils.StructToMap(r.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := make([]string, len(r.Query.Dimensions))
+	for i, k := range r.Query.Dimensions {
+		dimensions[i] = opts.Keys.Key(k)
+	}
+
+	metrics := make([]string, len(r.Query.Metrics))
+	for i, k := range r.Query.Metrics {
+		metrics[i] = opts.Keys.Key(k)
+	}
+
+	msgs := make(service.MessageBatch, 0, r.Query.Limit)
+
+	for n := 0; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the first row after header contains totals and averages
+		if n == 0 && isTotalsRecord(record, len(dimensions)) {
+			continue
+		}
+
+		if len(record) != len(dimensions)+len(metrics) {
+			return nil, fmt.Errorf("unexpected number of CSV columns: got %d, want %d", len(record), len(dimensions)+len(metrics))
+		}
+
+		r.Rows++
+
+		row := make(map[string]any, len(record))
+
+		for di, k := range dimensions {
+			row[k] = record[di]
+		}
+
+		for mi, k := range metrics {
+			v := record[len(dimensions)+mi]
+			if len(v) == 0 {
+				row[k] = nil
+
+				continue
+			}
+
+			m, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse metric %q value %q: %w", r.Query.Metrics[mi], v, err)
+			}
+
+			row[k] = metricValue(r.Query.Metrics[mi], &m, opts.Catalog)
+		}
+
+		for _, shaped := range shapeRows(opts.Shape, row, metrics) {
+			msg := service.NewMessage(nil)
+			msg.SetStructuredMut(shaped)
+			msg.MetaSetMut("row_type", "data")
+			msg.MetaSetMut("query", query)
+			msg.MetaSetMut("limit", r.Query.Limit)
+			msg.MetaSetMut("offset", r.Query.Offset)
+
+			if opts.Catalog != nil {
+				msg.MetaSetMut("metric_types", metricTypes(opts.Catalog, r.Query.Metrics, opts.Keys))
+			}
+
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs, nil
+}
+
+// totalsLabels are the localized labels of the "Total and averages" CSV row in lower case.
+var totalsLabels = []string{
+	"total and averages",
+	"итого и средние",
+}
+
+// isTotalsRecord reports whether the CSV record is the "Total and averages" row:
+// the first dimension has the exact totals label and other dimensions are empty.
+// It should be called for the row right after the header only.
+func isTotalsRecord(record []string, dimensions int) bool {
+	if dimensions == 0 || len(record) < dimensions {
+		return false
+	}
+
+	label := strings.ToLower(strings.TrimSpace(record[0]))
+	if !slices.Contains(totalsLabels, label) {
+		return false
+	}
+
+	for _, v := range record[1:dimensions] {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,538 @@
+package stattable
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponse_Batch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		statResponse   Response
+		expectedBatch  service.MessageBatch
+		expectedError  error
+		expectedLength int
+	}{
+		{
+			name: "Successful batch creation",
+			statResponse: Response{
+				Query: &Query{
+					IDs:        []int{123},
+					Metrics:    []string{"ym:s:visits", "ym:s:pageviews"},
+					Dimensions: []string{"ym:s:date"},
+					Date1:      "2023-10-26",
+					Date2:      "2023-10-27",
+					Limit:      10,
+					Offset:     0,
+				},
+				Data: []ResponseEntry{
+					{
+						Dimensions: []struct {
+							Name string `json:"name"`
+							Id   string `json:"id,omitempty"`
+						}{
+							{Name: "2023-10-26"},
+						},
+						Metrics: []*float64{ptr(100.0), ptr(200.0)},
+					},
+					{
+						Dimensions: []struct {
+							Name string `json:"name"`
+							Id   string `json:"id,omitempty"`
+						}{
+							{Name: "2023-10-27"},
+						},
+						Metrics: []*float64{ptr(150.0), ptr(250.0)},
+					},
+				},
+				TotalRows: 2,
+			},
+			expectedBatch: func() service.MessageBatch {
+				msg1 := service.NewMessage(nil)
+				msg1.SetStructuredMut(
+					map[string]any{
+						"date":      "2023-10-26",
+						"visits":    100.0,
+						"pageviews": 200.0,
+					})
+				msg1.MetaSetMut("query", map[string]any{
+					"ids":        []int{123},
+					"metrics":    []string{"ym:s:visits", "ym:s:pageviews"},
+					"dimensions": []string{"ym:s:date"},
+					"limit":      10,
+					"date1":      "2023-10-26",
+					"date2":      "2023-10-27",
+				})
+				msg1.MetaSetMut("limit", 10)
+				msg1.MetaSetMut("offset", 0)
+				msg1.MetaSetMut("total", 2)
+
+				msg2 := service.NewMessage(nil)
+				msg2.SetStructuredMut(map[string]any{
+					"date":      "2023-10-27",
+					"visits":    150.0,
+					"pageviews": 250.0,
+				})
+				msg2.MetaSetMut("query", map[string]any{
+					"ids":        []int{123},
+					"metrics":    []string{"ym:s:visits", "ym:s:pageviews"},
+					"dimensions": []string{"ym:s:date"},
+					"limit":      10,
+					"date1":      "2023-10-26",
+					"date2":      "2023-10-27",
+				})
+				msg2.MetaSetMut("limit", 10)
+				msg2.MetaSetMut("offset", 0)
+				msg2.MetaSetMut("total", 2)
+
+				return service.MessageBatch{msg1, msg2}
+			}(),
+			expectedError:  nil,
+			expectedLength: 2,
+		},
+		{
+			name: "Empty Data",
+			statResponse: Response{
+				Query: &Query{
+					IDs:        []int{123},
+					Metrics:    []string{"ym:s:visits"},
+					Dimensions: []string{"ym:s:date"},
+				},
+				Data:      []ResponseEntry{},
+				TotalRows: 0,
+			},
+			expectedBatch:  nil,
+			expectedError:  nil,
+			expectedLength: 0,
+		},
+		{
+			name: "Nil Data",
+			statResponse: Response{
+				Query: &Query{
+					IDs:        []int{123},
+					Metrics:    []string{"ym:s:visits"},
+					Dimensions: []string{"ym:s:date"},
+				},
+				TotalRows: 0,
+			},
+			expectedBatch:  nil,
+			expectedError:  nil,
+			expectedLength: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			batch, err := tc.statResponse.Batch()
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedLength, len(batch))
+
+				for i := range tc.expectedBatch {
+					expectedMsg, _ := tc.expectedBatch[i].AsStructured()
+					realMsg, _ := batch[i].AsStructured()
+					assert.Equal(t, expectedMsg, realMsg)
+
+					meta := []string{"query", "limit", "offset", "total"}
+					for _, m := range meta {
+						expectedMeta, _ := tc.expectedBatch[i].MetaGetMut(m)
+						realMeta, _ := batch[i].MetaGetMut(m)
+						assert.Equal(t, expectedMeta, realMeta)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCSVResponse_Batch(t *testing.T) {
+	q := &Query{
+		IDs:        []int{123},
+		Metrics:    []string{"ym:s:visits"},
+		Dimensions: []string{"ym:s:date"},
+	}
+
+	testCases := []struct {
+		name          string
+		body          string
+		expected      []map[string]any
+		expectedError error
+	}{
+		{
+			name:     "Without totals",
+			body:     "date,visits\n2023-10-26,100\n",
+			expected: []map[string]any{{"date": "2023-10-26", "visits": 100.0}},
+		},
+		{
+			name:     "Russian totals",
+			body:     "Дата визита,Визиты\nИтого и средние,100\n2023-10-26,100\n",
+			expected: []map[string]any{{"date": "2023-10-26", "visits": 100.0}},
+		},
+		{
+			name:     "English totals",
+			body:     "Date of visit,Sessions\n\"Total and averages\",100\n2023-10-26,100\n",
+			expected: []map[string]any{{"date": "2023-10-26", "visits": 100.0}},
+		},
+		{
+			name: "Dimension values like totals",
+			body: "Date of visit,Sessions\nTotal Commander,100\nИтоговый,50\nTotal and averages,150\n",
+			expected: []map[string]any{
+				{"date": "Total Commander", "visits": 100.0},
+				{"date": "Итоговый", "visits": 50.0},
+				{"date": "Total and averages", "visits": 150.0},
+			},
+		},
+		{
+			name:     "Empty body",
+			body:     "",
+			expected: nil,
+		},
+		{
+			name:          "Invalid metric",
+			body:          "date,visits\n2023-10-26,abc\n",
+			expectedError: errors.New(`cannot parse metric "ym:s:visits" value "abc": strconv.ParseFloat: parsing "abc": invalid syntax`),
+		},
+		{
+			name:          "Unexpected columns",
+			body:          "date,visits\n2023-10-26,100,200\n",
+			expectedError: errors.New(`unexpected number of CSV columns: got 3, want 2`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &CSVResponse{
+				Query: q,
+				Body:  io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			batch, err := resp.Batch()
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, batch, len(tc.expected))
+
+			for i, msg := range batch {
+				realMsg, _ := msg.AsStructured()
+				assert.Equal(t, tc.expected[i], realMsg)
+			}
+		})
+	}
+}
+
+func TestResponse_BatchWithOptions(t *testing.T) {
+	resp := Response{
+		Query: &Query{
+			IDs:        []int{123},
+			Metrics:    []string{"ym:s:visits"},
+			Dimensions: []string{"ym:s:date", "ym:s:regionCountry"},
+		},
+		Data: []ResponseEntry{
+			{
+				Dimensions: []struct {
+					Name string `json:"name"`
+					Id   string `json:"id,omitempty"`
+				}{
+					{Name: "2023-10-26"},
+					{Name: "Russia", Id: "225"},
+				},
+				Metrics: []*float64{ptr(100.0)},
+			},
+		},
+		TotalRows: 1,
+	}
+
+	testCases := []struct {
+		name     string
+		opts     BatchOptions
+		expected map[string]any
+	}{
+		{
+			name: "Names only",
+			opts: BatchOptions{DimensionIDs: DimensionIDsNone},
+			expected: map[string]any{
+				"date":           "2023-10-26",
+				"region_country": "Russia",
+				"visits":         100.0,
+			},
+		},
+		{
+			name: "ID fields",
+			opts: BatchOptions{DimensionIDs: DimensionIDsField},
+			expected: map[string]any{
+				"date":              "2023-10-26",
+				"date_id":           nil,
+				"region_country":    "Russia",
+				"region_country_id": "225",
+				"visits":            100.0,
+			},
+		},
+		{
+			name: "ID objects",
+			opts: BatchOptions{DimensionIDs: DimensionIDsObject},
+			expected: map[string]any{
+				"date":           map[string]any{"name": "2023-10-26", "id": nil},
+				"region_country": map[string]any{"name": "Russia", "id": "225"},
+				"visits":         100.0,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			batch, err := resp.BatchWithOptions(tc.opts)
+			assert.NoError(t, err)
+			assert.Len(t, batch, 1)
+
+			realMsg, _ := batch[0].AsStructured()
+			assert.Equal(t, tc.expected, realMsg)
+		})
+	}
+}
+
+func TestResponse_Meta(t *testing.T) {
+	var resp Response
+
+	err := json.Unmarshal([]byte(`{
+		"query": {
+			"ids": [123],
+			"metrics": ["ym:s:visits", "ym:s:bounceRate"],
+			"dimensions": ["ym:s:date"]
+		},
+		"data": [{ "dimensions": [{ "name": "2023-10-26" }], "metrics": [100, 12.5] }],
+		"total_rows": 1,
+		"totals": [100, 12.5],
+		"min": [100, 12.5],
+		"max": [100, 12.5],
+		"sampled": true,
+		"sample_share": 0.1,
+		"sample_size": 1000,
+		"sample_space": 10000,
+		"data_lag": 120,
+		"contains_sensitive_data": false
+	}`), &resp)
+	assert.NoError(t, err)
+
+	batch, err := resp.Batch()
+	assert.NoError(t, err)
+	assert.Len(t, batch, 1)
+
+	totals := map[string]any{"visits": 100.0, "bounce_rate": 12.5}
+
+	expectedMeta := map[string]any{
+		"row_type":                "data",
+		"total":                   1,
+		"totals":                  totals,
+		"min":                     totals,
+		"max":                     totals,
+		"sampled":                 true,
+		"sample_share":            0.1,
+		"sample_size":             int64(1000),
+		"sample_space":            int64(10000),
+		"data_lag":                120,
+		"contains_sensitive_data": false,
+	}
+
+	for k, v := range expectedMeta {
+		realMeta, ok := batch[0].MetaGetMut(k)
+		assert.True(t, ok, k)
+		assert.Equal(t, v, realMeta, k)
+	}
+
+	msg, err := resp.TotalsMessage(BatchOptions{})
+	assert.NoError(t, err)
+
+	realMsg, _ := msg.AsStructured()
+	assert.Equal(t, totals, realMsg)
+
+	rowType, _ := msg.MetaGetMut("row_type")
+	assert.Equal(t, "totals", rowType)
+}
+
+func TestResponse_BatchLongShape(t *testing.T) {
+	resp := Response{
+		Query: &Query{
+			IDs:        []int{123},
+			Metrics:    []string{"ym:s:visits", "ym:s:pageviews"},
+			Dimensions: []string{"ym:s:date"},
+		},
+		Data: []ResponseEntry{
+			{
+				Dimensions: []struct {
+					Name string `json:"name"`
+					Id   string `json:"id,omitempty"`
+				}{
+					{Name: "2023-10-26"},
+				},
+				Metrics: []*float64{ptr(100.0), ptr(200.0)},
+			},
+		},
+		TotalRows: 1,
+	}
+
+	batch, err := resp.BatchWithOptions(BatchOptions{Shape: ShapeLong})
+	assert.NoError(t, err)
+	assert.Len(t, batch, 2)
+
+	expected := []map[string]any{
+		{"date": "2023-10-26", "metric": "visits", "value": 100.0},
+		{"date": "2023-10-26", "metric": "pageviews", "value": 200.0},
+	}
+
+	for i, msg := range batch {
+		realMsg, _ := msg.AsStructured()
+		assert.Equal(t, expected[i], realMsg)
+
+		total, _ := msg.MetaGetMut("total")
+		assert.Equal(t, 1, total)
+	}
+}
+
+func TestResponse_BatchTypedMetrics(t *testing.T) {
+	var resp Response
+
+	err := json.Unmarshal([]byte(`{
+		"query": {
+			"ids": [123],
+			"metrics": ["ym:s:visits", "ym:s:bounceRate", "ym:s:avgVisitDurationSeconds", "ym:s:newMetric"],
+			"dimensions": ["ym:s:date"]
+		},
+		"data": [{ "dimensions": [{ "name": "2023-10-26" }], "metrics": [1234, 12.5, null, 3] }],
+		"total_rows": 1
+	}`), &resp)
+	assert.NoError(t, err)
+
+	batch, err := resp.BatchWithOptions(BatchOptions{Catalog: catalog.Metrika})
+	assert.NoError(t, err)
+	assert.Len(t, batch, 1)
+
+	realMsg, _ := batch[0].AsStructured()
+	assert.Equal(t, map[string]any{
+		"date":                       "2023-10-26",
+		"visits":                     int64(1234),
+		"bounce_rate":                12.5,
+		"avg_visit_duration_seconds": nil,
+		"new_metric":                 3.0,
+	}, realMsg)
+
+	metricTypes, _ := batch[0].MetaGetMut("metric_types")
+	assert.Equal(t, map[string]any{
+		"visits":                     "integer",
+		"bounce_rate":                "percent",
+		"avg_visit_duration_seconds": "duration",
+	}, metricTypes)
+
+	batch, err = resp.Batch()
+	assert.NoError(t, err)
+
+	realMsg, _ = batch[0].AsStructured()
+	assert.Equal(t, map[string]any{
+		"date":                       "2023-10-26",
+		"visits":                     1234.0,
+		"bounce_rate":                12.5,
+		"avg_visit_duration_seconds": nil,
+		"new_metric":                 3.0,
+	}, realMsg)
+}
+
+func TestResponse_BatchKeys(t *testing.T) {
+	resp := Response{
+		Query: &Query{
+			IDs:        []int{123},
+			Metrics:    []string{"ym:s:visits"},
+			Dimensions: []string{"ym:s:lastTrafficSource"},
+		},
+		Data: []ResponseEntry{
+			{
+				Dimensions: []struct {
+					Name string `json:"name"`
+					Id   string `json:"id,omitempty"`
+				}{
+					{Name: "Direct traffic"},
+				},
+				Metrics: []*float64{ptr(100.0)},
+			},
+		},
+		TotalRows: 1,
+	}
+
+	tests := []struct {
+		name     string
+		keys     utils.KeyMapper
+		expected map[string]any
+	}{
+		{
+			name:     "default",
+			expected: map[string]any{"last_traffic_source": "Direct traffic", "visits": 100.0},
+		},
+		{
+			name:     "raw",
+			keys:     utils.KeyMapper{Strategy: utils.KeyStrategyRaw},
+			expected: map[string]any{"ym:s:lastTrafficSource": "Direct traffic", "ym:s:visits": 100.0},
+		},
+		{
+			name:     "stripped",
+			keys:     utils.KeyMapper{Strategy: utils.KeyStrategyStripped},
+			expected: map[string]any{"lastTrafficSource": "Direct traffic", "visits": 100.0},
+		},
+		{
+			name:     "alias",
+			keys:     utils.KeyMapper{Strategy: utils.KeyStrategyAlias, Aliases: map[string]string{"ym:s:visits": "sessions"}},
+			expected: map[string]any{"ym:s:lastTrafficSource": "Direct traffic", "sessions": 100.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := resp.BatchWithOptions(BatchOptions{Keys: tt.keys})
+			assert.NoError(t, err)
+			assert.Len(t, batch, 1)
+
+			realMsg, _ := batch[0].AsStructured()
+			assert.Equal(t, tt.expected, realMsg)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestIsTotalsRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     []string
+		dimensions int
+		expected   bool
+	}{
+		{name: "english", record: []string{"Total and averages", "100"}, dimensions: 1, expected: true},
+		{name: "russian", record: []string{"Итого и средние", "100"}, dimensions: 1, expected: true},
+		{name: "case and spaces", record: []string{" TOTAL AND AVERAGES ", "100"}, dimensions: 1, expected: true},
+		{name: "empty dimensions", record: []string{"Total and averages", "", "100"}, dimensions: 2, expected: true},
+		{name: "prefix", record: []string{"Total Commander", "100"}, dimensions: 1, expected: false},
+		{name: "russian prefix", record: []string{"Итоговый", "100"}, dimensions: 1, expected: false},
+		{name: "other dimension", record: []string{"Total and averages", "Russia", "100"}, dimensions: 2, expected: false},
+		{name: "no dimensions", record: []string{"100"}, dimensions: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isTotalsRecord(tt.record, tt.dimensions))
+		})
+	}
+}
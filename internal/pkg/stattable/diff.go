@@ -0,0 +1,316 @@
+package stattable
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// Row changes of the diff mode.
+const (
+	changeNew     = "new"
+	changeChanged = "changed"
+	changeDeleted = "deleted"
+)
+
+// diffIndexKey is the cache key of the row keys seen in the previous poll.
+const diffIndexKey = "index"
+
+// Differ emits only new and changed rows comparing row hashes with the previous poll.
+// Row hashes are stored in a cache resource keyed by the row dimension values.
+type Differ struct {
+	cache       string
+	prefix      string
+	ttl         *time.Duration
+	emitDeleted bool
+	keys        utils.KeyMapper // keys converts metric names to the row keys of the metric values.
+	mgr         *service.Resources
+	mut         sync.Mutex
+	seen        map[string]map[string]any // seen maps row keys of the current poll to their dimension values.
+}
+
+// diffPending is a cache update of a batch. It is committed when the batch is acknowledged,
+// so the rows failed to deliver are emitted again by the next poll.
+type diffPending struct {
+	sums    map[string]string // sums maps row keys to the row hashes.
+	deleted []string          // deleted is a list of row keys of the tombstones.
+	index   string            // index is the row keys of the poll, it is set with the tombstones.
+}
+
+// Filter drops unchanged data rows of the step and tags the rest with the `change` metadata field.
+// Other messages, e.g. totals, are returned as is. Hashes of the returned rows are not stored
+// until the pending update is committed.
+func (d *Differ) Filter(ctx context.Context, step step, msgs service.MessageBatch) (service.MessageBatch, *diffPending, error) {
+	values := valueKeys(step.query.Metrics, d.keys)
+	filtered := make(service.MessageBatch, 0, len(msgs))
+	pending := &diffPending{sums: map[string]string{}}
+
+	for _, msg := range msgs {
+		if rowType, _ := msg.MetaGetMut("row_type"); rowType != "data" {
+			filtered = append(filtered, msg)
+
+			continue
+		}
+
+		v, err := msg.AsStructured()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		row, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil, errors.New("diff mode supports object rows only")
+		}
+
+		dims := make(map[string]any, len(row))
+
+		for k, v := range row {
+			if !slices.Contains(values, k) {
+				dims[k] = v
+			}
+		}
+
+		// rows of different date windows and metrics chunks may have equal dimension values
+		var window string
+		if step.window != nil {
+			window = step.window.String()
+		}
+
+		key, err := hashValue([]any{window, step.part, dims})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sum, err := hashValue(row)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		prev, err := d.get(ctx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		d.mut.Lock()
+		d.seen[key] = dims
+		d.mut.Unlock()
+
+		if prev == sum {
+			continue
+		}
+
+		pending.sums[key] = sum
+
+		change := changeNew
+		if prev != "" {
+			change = changeChanged
+		}
+
+		msg.MetaSetMut("change", change)
+
+		filtered = append(filtered, msg)
+	}
+
+	return filtered, pending, nil
+}
+
+// Tombstones returns messages for the rows of the previous poll missing from the current one.
+// It should be called after a complete poll only. The seen rows become the index of the next poll:
+// without tombstones the index is stored at once, otherwise the tombstone keys are deleted
+// and the index is stored when the pending update is committed.
+func (d *Differ) Tombstones(ctx context.Context) (service.MessageBatch, *diffPending, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	if !d.emitDeleted {
+		return nil, nil, nil
+	}
+
+	raw, err := d.get(ctx, diffIndexKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var prev map[string]map[string]any
+
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &prev); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		msgs    service.MessageBatch
+		deleted []string
+	)
+
+	for key, dims := range prev {
+		if _, ok := d.seen[key]; ok {
+			continue
+		}
+
+		deleted = append(deleted, key)
+
+		msg := service.NewMessage(nil)
+		msg.SetStructuredMut(dims)
+		msg.MetaSetMut("row_type", "data")
+		msg.MetaSetMut("change", changeDeleted)
+
+		msgs = append(msgs, msg)
+	}
+
+	index, err := json.Marshal(d.seen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(msgs) == 0 {
+		return nil, nil, d.set(ctx, diffIndexKey, string(index))
+	}
+
+	return msgs, &diffPending{deleted: deleted, index: string(index)}, nil
+}
+
+// commit stores the row hashes, deletes the tombstone keys and stores the index of the pending update.
+func (d *Differ) commit(ctx context.Context, p *diffPending) error {
+	for key, sum := range p.sums {
+		if err := d.set(ctx, key, sum); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range p.deleted {
+		if err := d.delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	if p.index != "" {
+		return d.set(ctx, diffIndexKey, p.index)
+	}
+
+	return nil
+}
+
+func (d *Differ) get(ctx context.Context, key string) (string, error) {
+	var (
+		value []byte
+		err   error
+	)
+
+	accessErr := d.mgr.AccessCache(ctx, d.cache, func(c service.Cache) {
+		value, err = c.Get(ctx, d.prefix+key)
+	})
+	if accessErr != nil {
+		return "", accessErr
+	}
+
+	if errors.Is(err, service.ErrKeyNotFound) {
+		return "", nil
+	}
+
+	return string(value), err
+}
+
+func (d *Differ) set(ctx context.Context, key, value string) error {
+	var err error
+
+	accessErr := d.mgr.AccessCache(ctx, d.cache, func(c service.Cache) {
+		err = c.Set(ctx, d.prefix+key, []byte(value), d.ttl)
+	})
+	if accessErr != nil {
+		return accessErr
+	}
+
+	return err
+}
+
+func (d *Differ) delete(ctx context.Context, key string) error {
+	var err error
+
+	accessErr := d.mgr.AccessCache(ctx, d.cache, func(c service.Cache) {
+		err = c.Delete(ctx, d.prefix+key)
+	})
+	if accessErr != nil {
+		return accessErr
+	}
+
+	if errors.Is(err, service.ErrKeyNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// valueKeys returns the row keys of the metric values in the wide and long shapes.
+func valueKeys(metrics []string, mapper utils.KeyMapper) []string {
+	keys := make([]string, 0, len(metrics)+1)
+
+	for _, m := range metrics {
+		keys = append(keys, mapper.Key(m))
+	}
+
+	return append(keys, "value")
+}
+
+// hashValue returns a hex encoded SHA-256 hash of the value JSON representation.
+// Map keys are sorted by the JSON encoder, so equal rows have equal hashes.
+func hashValue(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// DifferFromConfig parses the diff config object.
+func DifferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*Differ, error) {
+	d := &Differ{
+		mgr:  mgr,
+		seen: map[string]map[string]any{},
+	}
+
+	var err error
+
+	d.cache, err = conf.FieldString("cache")
+	if err != nil {
+		return nil, err
+	}
+
+	if !mgr.HasCache(d.cache) {
+		return nil, fmt.Errorf("cache resource %q not found", d.cache)
+	}
+
+	d.prefix, err = conf.FieldString("key_prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains("ttl") {
+		ttl, err := conf.FieldDuration("ttl")
+		if err != nil {
+			return nil, err
+		}
+
+		d.ttl = &ttl
+	}
+
+	d.emitDeleted, err = conf.FieldBool("emit_deleted")
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
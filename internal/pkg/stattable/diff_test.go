@@ -0,0 +1,153 @@
+package stattable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiffer(emitDeleted bool) *Differ {
+	return &Differ{
+		cache:       "diff",
+		emitDeleted: emitDeleted,
+		mgr:         service.MockResources(service.MockResourcesOptAddCache("diff")),
+		seen:        map[string]map[string]any{},
+	}
+}
+
+func diffRow(source string, visits float64) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(map[string]any{"source": source, "visits": visits})
+	msg.MetaSetMut("row_type", "data")
+
+	return msg
+}
+
+func diffChanges(msgs service.MessageBatch) map[string]string {
+	changes := make(map[string]string, len(msgs))
+
+	for _, msg := range msgs {
+		v, _ := msg.AsStructured()
+		change, _ := msg.MetaGet("change")
+		changes[v.(map[string]any)["source"].(string)] = change
+	}
+
+	return changes
+}
+
+func TestDiffer_Filter(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDiffer(false)
+	s := step{query: &Query{Metrics: []string{"ym:s:visits"}}, part: 1, parts: 1}
+
+	msgs, pending, err := d.Filter(ctx, s, service.MessageBatch{diffRow("ads", 1), diffRow("search", 2)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ads": changeNew, "search": changeNew}, diffChanges(msgs))
+
+	// hashes of a batch which is not delivered are not stored
+	msgs, _, err = d.Filter(ctx, s, service.MessageBatch{diffRow("ads", 1), diffRow("search", 2)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ads": changeNew, "search": changeNew}, diffChanges(msgs))
+
+	require.NoError(t, d.commit(ctx, pending))
+
+	msgs, _, err = d.Filter(ctx, s, service.MessageBatch{diffRow("ads", 1), diffRow("search", 3), diffRow("direct", 4)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"search": changeChanged, "direct": changeNew}, diffChanges(msgs))
+
+	totals := service.NewMessage(nil)
+	totals.SetStructuredMut(map[string]any{"visits": 10.0})
+	totals.MetaSetMut("row_type", "totals")
+
+	msgs, _, err = d.Filter(ctx, s, service.MessageBatch{totals})
+	require.NoError(t, err)
+	assert.Len(t, msgs, 1)
+}
+
+func TestDiffer_Tombstones(t *testing.T) {
+	ctx := context.Background()
+	s := step{query: &Query{Metrics: []string{"ym:s:visits"}}, part: 1, parts: 1}
+	mgr := service.MockResources(service.MockResourcesOptAddCache("diff"))
+
+	poll := func(rows ...*service.Message) *Differ {
+		d := newTestDiffer(true)
+		d.mgr = mgr
+
+		_, pending, err := d.Filter(ctx, s, rows)
+		require.NoError(t, err)
+		require.NoError(t, d.commit(ctx, pending))
+
+		return d
+	}
+
+	d := poll(diffRow("ads", 1), diffRow("search", 2))
+
+	msgs, pending, err := d.Tombstones(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Nil(t, pending)
+
+	d = poll(diffRow("ads", 1))
+
+	msgs, pending, err = d.Tombstones(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"search": changeDeleted}, diffChanges(msgs))
+
+	// tombstones which are not delivered are emitted again
+	d = poll(diffRow("ads", 1))
+
+	msgs, _, err = d.Tombstones(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"search": changeDeleted}, diffChanges(msgs))
+
+	require.NoError(t, d.commit(ctx, pending))
+
+	d = poll(diffRow("ads", 1))
+
+	msgs, _, err = d.Tombstones(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
+
+func TestHashValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        any
+		b        any
+		expected bool
+	}{
+		{
+			name:     "Equal rows",
+			a:        map[string]any{"source": "ads", "visits": 1.0},
+			b:        map[string]any{"visits": 1.0, "source": "ads"},
+			expected: true,
+		},
+		{
+			name:     "Different values",
+			a:        map[string]any{"source": "ads", "visits": 1.0},
+			b:        map[string]any{"source": "ads", "visits": 2.0},
+			expected: false,
+		},
+		{
+			name:     "Different windows",
+			a:        []any{"2024-01-01..2024-01-01", 1, map[string]any{"source": "ads"}},
+			b:        []any{"2024-01-02..2024-01-02", 1, map[string]any{"source": "ads"}},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := hashValue(tc.a)
+			require.NoError(t, err)
+
+			b, err := hashValue(tc.b)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, a == b)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package stattable
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// IDsAll is the ids value to read all counters or applications available to the token.
+const IDsAll = "all"
+
+// IDsLintRule returns the lint rule of the ids field which accepts a list of IDs,
+// `all` or an object with the name and label patterns. The kind is a noun
+// of the IDs in the lint messages, e.g. `counter`.
+func IDsLintRule(kind string) string {
+	return strings.NewReplacer("<kind>", kind).Replace(`root = match {
+  this.type() == "array" => if this.length() == 0 || !this.all(i -> i.type() == "number") { ["ids must be a non-empty list of <kind> IDs"] } else { [] },
+  this.type() == "string" => if this != "all" { ["ids must be a list of <kind> IDs, all or an object with the name or label pattern"] } else { [] },
+  this.type() == "object" => if this.keys().length() == 0 || this.keys().any(k -> !["name", "label"].contains(k)) { ["ids object must have the name or label keys only"] } else { [] },
+  _ => ["ids must be a list of <kind> IDs, all or an object with the name or label pattern"],
+}`)
+}
+
+// Selector selects counters or applications by name and label patterns.
+// A selector without patterns selects all of them.
+type Selector struct {
+	Name  *regexp.Regexp // Name is a pattern of the name.
+	Label *regexp.Regexp // Label is a pattern of any label.
+}
+
+// Match reports whether the name and any of the labels match the patterns.
+func (s *Selector) Match(name string, labels ...string) bool {
+	if s.Name != nil && !s.Name.MatchString(name) {
+		return false
+	}
+
+	if s.Label != nil && !slices.ContainsFunc(labels, s.Label.MatchString) {
+		return false
+	}
+
+	return true
+}
+
+// Entry is a counter or an application the selector is matched with.
+type Entry struct {
+	ID      int      // ID is the counter or application ID.
+	Name    string   // Name is the counter or application name.
+	Labels  []string // Labels are the labels or the folder of the entry.
+	Deleted bool     // Deleted reports whether the entry is deleted.
+}
+
+// Select returns IDs of the entries matching the selector.
+// Deleted entries are skipped, since their reports fail.
+func (s *Selector) Select(entries []Entry) []int {
+	var ids []int
+
+	for _, e := range entries {
+		if !e.Deleted && s.Match(e.Name, e.Labels...) {
+			ids = append(ids, e.ID)
+		}
+	}
+
+	return ids
+}
+
+// IDsFromConfig parses the ids field: a list of IDs or a selector.
+// The kind is a noun of the IDs in the error messages, e.g. `counter`.
+func IDsFromConfig(conf *service.ParsedConfig, kind string) ([]int, *Selector, error) {
+	if ids, err := conf.FieldIntList("ids"); err == nil {
+		return ids, nil, nil
+	}
+
+	if v, err := conf.FieldString("ids"); err == nil {
+		if v != IDsAll {
+			return nil, nil, fmt.Errorf("unsupported ids value %q", v)
+		}
+
+		return nil, &Selector{}, nil
+	}
+
+	ns := conf.Namespace("ids")
+	s := &Selector{}
+
+	if ns.Contains("name") {
+		pattern, err := ns.FieldString("name")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		s.Name, err = regexp.Compile(pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if ns.Contains("label") {
+		pattern, err := ns.FieldString("label")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		s.Label, err = regexp.Compile(pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if s.Name == nil && s.Label == nil {
+		return nil, nil, fmt.Errorf("ids must be a list of %s IDs, all or an object with the name or label pattern", kind)
+	}
+
+	return nil, s, nil
+}
@@ -0,0 +1,105 @@
+package stattable
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector_Match(t *testing.T) {
+	testCases := []struct {
+		name     string
+		selector Selector
+		counter  string
+		labels   []string
+		expected bool
+	}{
+		{name: "All", selector: Selector{}, counter: "Shop", expected: true},
+		{name: "Name", selector: Selector{Name: regexp.MustCompile("^Shop")}, counter: "Shop RU", expected: true},
+		{name: "Other name", selector: Selector{Name: regexp.MustCompile("^Shop")}, counter: "Blog", expected: false},
+		{name: "Label", selector: Selector{Label: regexp.MustCompile("^prod$")}, counter: "Shop", labels: []string{"test", "prod"}, expected: true},
+		{name: "No labels", selector: Selector{Label: regexp.MustCompile("^prod$")}, counter: "Shop", expected: false},
+		{
+			name:     "Name and label",
+			selector: Selector{Name: regexp.MustCompile("^Shop"), Label: regexp.MustCompile("^prod$")},
+			counter:  "Blog",
+			labels:   []string{"prod"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.selector.Match(tc.counter, tc.labels...))
+		})
+	}
+}
+
+func TestSelector_Select(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Name: "Shop RU", Labels: []string{"prod"}},
+		{ID: 2, Name: "Shop KZ", Labels: []string{"prod"}, Deleted: true},
+		{ID: 3, Name: "Blog", Labels: []string{"test"}},
+	}
+
+	testCases := []struct {
+		name     string
+		selector Selector
+		expected []int
+	}{
+		{name: "All without deleted", selector: Selector{}, expected: []int{1, 3}},
+		{name: "Name without deleted", selector: Selector{Name: regexp.MustCompile("^Shop")}, expected: []int{1}},
+		{name: "Deleted only", selector: Selector{Name: regexp.MustCompile("KZ$")}, expected: nil},
+		{name: "Label", selector: Selector{Label: regexp.MustCompile("^test$")}, expected: []int{3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.selector.Select(entries))
+		})
+	}
+}
+
+func TestIDsFromConfig(t *testing.T) {
+	spec := service.NewConfigSpec().Field(service.NewAnyField("ids"))
+
+	testCases := []struct {
+		name             string
+		yaml             string
+		expectedIDs      []int
+		expectedSelector *Selector
+		expectError      bool
+	}{
+		{name: "IDs", yaml: "ids: [1, 2]", expectedIDs: []int{1, 2}},
+		{name: "All", yaml: "ids: all", expectedSelector: &Selector{}},
+		{name: "Unknown value", yaml: "ids: some", expectError: true},
+		{
+			name:             "Patterns",
+			yaml:             "ids: {name: '^Shop', label: prod}",
+			expectedSelector: &Selector{Name: regexp.MustCompile("^Shop"), Label: regexp.MustCompile("prod")},
+		},
+		{name: "Invalid pattern", yaml: "ids: {name: '('}", expectError: true},
+		{name: "Empty object", yaml: "ids: {}", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf, err := spec.ParseYAML(tc.yaml, nil)
+			require.NoError(t, err)
+
+			ids, selector, err := IDsFromConfig(conf, "counter")
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedSelector, selector)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package stattable
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// lintRule checks sort keys, dates, the number of metrics and dimensions and incompatible fields, e.g. the options the csv format does not support.
+// The <max_metrics> placeholder is the metrics limit of a query and <extra> is the input specific rules.
+const lintRule = `
+let fields = [$config_metrics, $config_dimensions].flatten()
+let date1 = if this.date1.or("").re_match("^[0-9]{4}-[0-9]{2}-[0-9]{2}$") { this.date1.replace_all("-", "").number() } else { null }
+let date2 = if this.date2.or("").re_match("^[0-9]{4}-[0-9]{2}-[0-9]{2}$") { this.date2.replace_all("-", "").number() } else { null }
+root = [
+  $catalog_lints,
+  this.sort.or([]).map_each(s -> s.string().trim_prefix("-")).filter(s -> !$fields.contains(s)).map_each(s -> "sort key %q is not in metrics or dimensions".format(s)),
+  if $date1 != null && $date2 != null && $date1 > $date2 { ["date1 must not be after date2"] } else { [] },
+  if $config_metrics.length() > <max_metrics> && !this.exists("max_metrics") { ["too many metrics, the maximum is <max_metrics>, set max_metrics to split them across queries"] } else { [] },
+  if $config_dimensions.length() > 10 { ["too many dimensions, the maximum is 10"] } else { [] },
+  if this.key_strategy.or("snake") == "alias" && !this.exists("key_aliases") { ["key_aliases must be set for the alias key strategy"] } else { [] },
+  if this.format.or("json") == "csv" && this.dimension_ids.or("none") != "none" { ["dimension_ids is not supported by the csv format"] } else { [] },
+  if this.format.or("json") == "csv" && this.emit_totals.or(false) { ["emit_totals is not supported by the csv format"] } else { [] },
+  if this.format.or("json") == "csv" && this.fail_on_sampled.or(false) { ["fail_on_sampled is not supported by the csv format"] } else { [] },<extra>
+].flatten()
+`
+
+// LintRule returns the config lint rule of the stat table inputs with the catalog checks.
+// The maxMetrics is the metrics limit of a query. The extra rules are Bloblang expressions
+// of the lint message arrays of the input specific fields.
+func LintRule(c *catalog.Catalog, maxMetrics int, extra ...string) string {
+	var sb strings.Builder
+
+	for _, rule := range extra {
+		sb.WriteString("\n  " + rule + ",")
+	}
+
+	return c.LintRule() + strings.NewReplacer(
+		"<max_metrics>", strconv.Itoa(maxMetrics),
+		"<extra>", sb.String(),
+	).Replace(lintRule)
+}
+
+// MaxMetricsField returns the max_metrics config field with the metrics limit of a query.
+func MaxMetricsField(maxMetrics int) *service.ConfigField {
+	limit := strconv.Itoa(maxMetrics)
+
+	return service.NewIntField("max_metrics").
+		Description("Maximum number of metrics per query. Longer metric lists are split across several queries, each row has the `metrics_part` and `metrics_parts` metadata fields. Without it a report is limited to " + limit + " metrics.").
+		Optional().
+		LintRule(`root = if this < 1 || this > ` + limit + ` { ["max_metrics must be in the [1; ` + limit + `] range"] }`)
+}
@@ -0,0 +1,78 @@
+package stattable
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintRule(t *testing.T) {
+	metrics := slices.Repeat([]any{"ym:s:visits"}, 3)
+
+	tests := []struct {
+		name     string
+		extra    []string
+		conf     map[string]any
+		expected []any
+	}{
+		{
+			name: "valid",
+			conf: map[string]any{
+				"metrics":    []any{"ym:s:visits"},
+				"dimensions": []any{"ym:s:date"},
+				"sort":       []any{"-ym:s:visits"},
+			},
+			expected: []any{},
+		},
+		{
+			name: "too many metrics",
+			conf: map[string]any{
+				"metrics": metrics,
+			},
+			expected: []any{"too many metrics, the maximum is 2, set max_metrics to split them across queries"},
+		},
+		{
+			name: "too many metrics with max_metrics",
+			conf: map[string]any{
+				"metrics":     metrics,
+				"max_metrics": 2,
+			},
+			expected: []any{},
+		},
+		{
+			name: "dates and csv",
+			conf: map[string]any{
+				"metrics":     []any{"ym:s:visits"},
+				"date1":       "2024-01-02",
+				"date2":       "2024-01-01",
+				"format":      "csv",
+				"emit_totals": true,
+			},
+			expected: []any{"date1 must not be after date2", "emit_totals is not supported by the csv format"},
+		},
+		{
+			name:  "extra",
+			extra: []string{`if this.exists("filter") { ["filter is set"] } else { [] }`},
+			conf: map[string]any{
+				"metrics": []any{"ym:s:visits"},
+				"filter":  "ym:s:isRobot=='No'",
+			},
+			expected: []any{"filter is set"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(LintRule(catalog.Metrika, 2, tt.extra...))
+			require.NoError(t, err)
+
+			res, err := exec.Query(tt.conf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package stattable
+
+import (
+	"context"
+	"slices"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// ExpandFunc expands parametrized metrics, dimensions or sort keys of the counters.
+type ExpandFunc func(ctx context.Context, counters []int, names []string) ([]string, error)
+
+// step is a single report query of the input plan.
+type step struct {
+	query     *Query
+	counter   int         // counter is set in the fan-out and settle modes
+	window    *dateWindow // window is set if the date range is split
+	part      int         // part is a metrics chunk number
+	parts     int         // parts is a number of metrics chunks
+	partition bool        // partition is set in the settle mode, the step covers a single counter and day
+}
+
+// setMeta tags the message with the counter, the date window, the metrics chunk and the partition of the step.
+// In the fan-out and settle modes the counter ID is added to the message fields too.
+func (s step) setMeta(msg *service.Message) error {
+	if s.counter != 0 {
+		msg.MetaSetMut("counter_id", s.counter)
+
+		row, err := msg.AsStructuredMut()
+		if err != nil {
+			return err
+		}
+
+		if row, ok := row.(map[string]any); ok {
+			row["counter_id"] = s.counter
+			msg.SetStructuredMut(row)
+		}
+	}
+
+	if s.window != nil {
+		msg.MetaSetMut("date1", s.query.Date1)
+		msg.MetaSetMut("date2", s.query.Date2)
+	}
+
+	if s.parts > 1 {
+		msg.MetaSetMut("metrics_part", s.part)
+		msg.MetaSetMut("metrics_parts", s.parts)
+	}
+
+	if s.partition {
+		msg.MetaSetMut("replace_partition", utils.ReplacePartition(s.counter, s.query.Date1))
+	}
+
+	return nil
+}
+
+// emptyMessage returns the marker of the step partition without rows in the settle mode,
+// so sinks can clear the rows of the partition read before.
+func (s step) emptyMessage() (*service.Message, error) {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(map[string]any{})
+	msg.MetaSetMut("row_type", "empty")
+
+	if err := s.setMeta(msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// buildPlan expands the base query into a list of queries:
+// one per counter in the fan-out and settle modes, date window and metrics chunk.
+// In the settle mode the date range is split into single days.
+// Parametrized fields are expanded per counter in the fan-out and settle modes,
+// so each counter query gets its own goals.
+func (r *Reader) buildPlan(ctx context.Context, base Query) ([]step, error) {
+	if !r.opts.FanOut && r.opts.SettleDays <= 0 {
+		if err := r.expand(ctx, &base); err != nil {
+			return nil, err
+		}
+
+		return r.buildSteps(ctx, base, 0)
+	}
+
+	var plan []step
+
+	for _, counter := range base.IDs {
+		q := base
+		q.IDs = []int{counter}
+
+		if err := r.expand(ctx, &q); err != nil {
+			return nil, err
+		}
+
+		steps, err := r.buildSteps(ctx, q, counter)
+		if err != nil {
+			return nil, err
+		}
+
+		plan = append(plan, steps...)
+	}
+
+	return plan, nil
+}
+
+// expand expands parametrized metrics, dimensions and sort keys of the query counters.
+func (r *Reader) expand(ctx context.Context, q *Query) error {
+	if r.opts.Expand == nil {
+		return nil
+	}
+
+	var err error
+
+	q.Metrics, err = r.opts.Expand(ctx, q.IDs, q.Metrics)
+	if err != nil {
+		return err
+	}
+
+	q.Dimensions, err = r.opts.Expand(ctx, q.IDs, q.Dimensions)
+	if err != nil {
+		return err
+	}
+
+	q.Sort, err = r.opts.Expand(ctx, q.IDs, q.Sort)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// buildSteps splits the expanded query into date windows and metrics chunks.
+// The counter is set in the fan-out and settle modes.
+func (r *Reader) buildSteps(ctx context.Context, base Query, counter int) ([]step, error) {
+	chunks := [][]string{base.Metrics}
+	if r.opts.MaxMetrics > 0 && len(base.Metrics) > 0 {
+		chunks = slices.Collect(slices.Chunk(base.Metrics, r.opts.MaxMetrics))
+	}
+
+	if len(chunks) > 1 {
+		r.logger.
+			With("counter_id", counter, "metrics", len(base.Metrics), "queries", len(chunks)).
+			Debug("metrics are split across queries")
+	}
+
+	var windows []*dateWindow
+
+	switch {
+	case r.opts.SettleDays > 0:
+		days, err := utils.DateRange(base.Date1, base.Date2)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range days {
+			w, err := newDateWindow(d, d)
+			if err != nil {
+				return nil, err
+			}
+
+			windows = append(windows, &w)
+		}
+
+		r.logger.
+			With("counter_id", counter, "days", len(days), "settle_days", r.opts.SettleDays).
+			Debug("report date range is split into days")
+	case r.opts.RequireUnsampled:
+		w, err := newDateWindow(base.Date1, base.Date2)
+		if err != nil {
+			return nil, err
+		}
+
+		probe := chunkQuery(base, chunks[0])
+
+		unsampled, err := r.planWindows(ctx, probe, w)
+		if err != nil {
+			return nil, err
+		}
+
+		r.logger.
+			With("counter_id", counter, "windows", len(unsampled)).
+			Debug("report date range is split into unsampled windows")
+
+		for _, w := range unsampled {
+			windows = append(windows, &w)
+		}
+	default:
+		windows = []*dateWindow{nil}
+	}
+
+	steps := make([]step, 0, len(windows)*len(chunks))
+
+	for _, w := range windows {
+		for ci, chunk := range chunks {
+			q := chunkQuery(base, chunk)
+
+			if w != nil {
+				q.Date1 = w.Date1.Format(dateLayout)
+				q.Date2 = w.Date2.Format(dateLayout)
+			}
+
+			steps = append(steps, step{
+				query:     &q,
+				counter:   counter,
+				window:    w,
+				part:      ci + 1,
+				parts:     len(chunks),
+				partition: r.opts.SettleDays > 0,
+			})
+		}
+	}
+
+	return steps, nil
+}
+
+// chunkQuery creates a copy of the query with a chunk of metrics.
+// Sort keys that are not in the chunk metrics or dimensions are dropped.
+func chunkQuery(base Query, metrics []string) Query {
+	q := base
+	q.Metrics = metrics
+
+	if len(base.Sort) == 0 || len(metrics) == len(base.Metrics) {
+		return q
+	}
+
+	q.Sort = make([]string, 0, len(base.Sort))
+
+	for _, k := range base.Sort {
+		name := k
+		if len(name) > 0 && name[0] == '-' {
+			name = name[1:]
+		}
+
+		if slices.Contains(metrics, name) || slices.Contains(base.Dimensions, name) {
+			q.Sort = append(q.Sort, k)
+		}
+	}
+
+	return q
+}
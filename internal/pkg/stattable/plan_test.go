@@ -0,0 +1,181 @@
+package stattable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_BuildPlan(t *testing.T) {
+	// expandGoals replaces the <goal_id> placeholder with the goal of the single counter
+	// and fails for several counters.
+	expandGoals := func(_ context.Context, counters []int, names []string) ([]string, error) {
+		expanded := make([]string, 0, len(names))
+
+		for _, name := range names {
+			if strings.Contains(name, "<goal_id>") && len(counters) != 1 {
+				return nil, fmt.Errorf("several counters: %v", counters)
+			}
+
+			if len(counters) == 1 {
+				name = strings.ReplaceAll(name, "<goal_id>", fmt.Sprint(counters[0]*10))
+			}
+
+			expanded = append(expanded, name)
+		}
+
+		return expanded, nil
+	}
+
+	base := Query{
+		IDs:        []int{1, 2},
+		Date1:      "2024-01-01",
+		Date2:      "2024-01-02",
+		Metrics:    []string{"ym:s:visits", "ym:s:users", "ym:s:pageviews"},
+		Dimensions: []string{"ym:s:date"},
+	}
+
+	type expectedStep struct {
+		ids     []int
+		counter int
+		dates   string
+		metrics []string
+		part    int
+		parts   int
+	}
+
+	testCases := []struct {
+		name          string
+		opts          Options
+		query         Query
+		expectedSteps []expectedStep
+		expectError   bool
+	}{
+		{
+			name:  "Single query",
+			opts:  Options{},
+			query: base,
+			expectedSteps: []expectedStep{
+				{ids: []int{1, 2}, dates: "2024-01-01..2024-01-02", metrics: base.Metrics, part: 1, parts: 1},
+			},
+		},
+		{
+			name:  "Metrics chunks",
+			opts:  Options{MaxMetrics: 2},
+			query: base,
+			expectedSteps: []expectedStep{
+				{ids: []int{1, 2}, dates: "2024-01-01..2024-01-02", metrics: []string{"ym:s:visits", "ym:s:users"}, part: 1, parts: 2},
+				{ids: []int{1, 2}, dates: "2024-01-01..2024-01-02", metrics: []string{"ym:s:pageviews"}, part: 2, parts: 2},
+			},
+		},
+		{
+			name:  "Fan-out",
+			opts:  Options{FanOut: true},
+			query: base,
+			expectedSteps: []expectedStep{
+				{ids: []int{1}, counter: 1, dates: "2024-01-01..2024-01-02", metrics: base.Metrics, part: 1, parts: 1},
+				{ids: []int{2}, counter: 2, dates: "2024-01-01..2024-01-02", metrics: base.Metrics, part: 1, parts: 1},
+			},
+		},
+		{
+			name:  "Settle days",
+			opts:  Options{SettleDays: 1},
+			query: base,
+			expectedSteps: []expectedStep{
+				{ids: []int{1}, counter: 1, dates: "2024-01-01..2024-01-01", metrics: base.Metrics, part: 1, parts: 1},
+				{ids: []int{1}, counter: 1, dates: "2024-01-02..2024-01-02", metrics: base.Metrics, part: 1, parts: 1},
+				{ids: []int{2}, counter: 2, dates: "2024-01-01..2024-01-01", metrics: base.Metrics, part: 1, parts: 1},
+				{ids: []int{2}, counter: 2, dates: "2024-01-02..2024-01-02", metrics: base.Metrics, part: 1, parts: 1},
+			},
+		},
+		{
+			name: "Placeholders per counter",
+			opts: Options{FanOut: true, Expand: expandGoals},
+			query: Query{
+				IDs:     []int{1, 2},
+				Date1:   "2024-01-01",
+				Date2:   "2024-01-02",
+				Metrics: []string{"ym:s:goal<goal_id>reaches"},
+			},
+			expectedSteps: []expectedStep{
+				{ids: []int{1}, counter: 1, dates: "2024-01-01..2024-01-02", metrics: []string{"ym:s:goal10reaches"}, part: 1, parts: 1},
+				{ids: []int{2}, counter: 2, dates: "2024-01-01..2024-01-02", metrics: []string{"ym:s:goal20reaches"}, part: 1, parts: 1},
+			},
+		},
+		{
+			name: "Placeholders of several counters",
+			opts: Options{Expand: expandGoals},
+			query: Query{
+				IDs:     []int{1, 2},
+				Date1:   "2024-01-01",
+				Date2:   "2024-01-02",
+				Metrics: []string{"ym:s:goal<goal_id>reaches"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := NewReader(&fakeClient{}, tc.opts, service.MockResources().Logger())
+
+			plan, err := reader.buildPlan(context.Background(), tc.query)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			actual := make([]expectedStep, 0, len(plan))
+
+			for _, s := range plan {
+				actual = append(actual, expectedStep{
+					ids:     s.query.IDs,
+					counter: s.counter,
+					dates:   s.query.Date1 + ".." + s.query.Date2,
+					metrics: s.query.Metrics,
+					part:    s.part,
+					parts:   s.parts,
+				})
+
+				assert.Equal(t, tc.opts.SettleDays > 0, s.partition)
+			}
+
+			assert.Equal(t, tc.expectedSteps, actual)
+		})
+	}
+}
+
+func TestChunkQuery(t *testing.T) {
+	base := Query{
+		Metrics:    []string{"ym:s:visits", "ym:s:users"},
+		Dimensions: []string{"ym:s:date"},
+		Sort:       []string{"-ym:s:visits", "ym:s:users", "ym:s:date"},
+	}
+
+	testCases := []struct {
+		name         string
+		metrics      []string
+		expectedSort []string
+	}{
+		{name: "All metrics", metrics: base.Metrics, expectedSort: base.Sort},
+		{name: "First chunk", metrics: []string{"ym:s:visits"}, expectedSort: []string{"-ym:s:visits", "ym:s:date"}},
+		{name: "Second chunk", metrics: []string{"ym:s:users"}, expectedSort: []string{"ym:s:users", "ym:s:date"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := chunkQuery(base, tc.metrics)
+			assert.Equal(t, tc.metrics, q.Metrics)
+			assert.Equal(t, tc.expectedSort, q.Sort)
+			assert.Equal(t, base.Metrics, []string{"ym:s:visits", "ym:s:users"})
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package stattable
+
+import (
+	"context"
+	"io"
+)
+
+// Client fetches the report pages. It is implemented by the stat table services
+// of the Yandex.Metrika and Yandex.AppMetrika API clients.
+type Client interface {
+	GetWithContext(ctx context.Context, q *Query) (*Response, error)
+	GetCSVWithContext(ctx context.Context, q *Query) (*CSVResponse, error)
+}
+
+// Query represents a query for fetching data from the Reporting API stat tables.
+type Query struct {
+	IDs          []int    `json:"ids" url:"ids,comma"`                                                       // IDs is a list of counter IDs.
+	Date1        string   `json:"date1,omitempty" url:"date1,omitempty"`                                     // Date1 is the start date of the data range.
+	Date2        string   `json:"date2,omitempty" url:"date2,omitempty"`                                     // Date2 is the end date of the data range.
+	Dimensions   []string `json:"dimensions,omitempty" url:"dimensions,comma,omitempty"`                     // Dimensions is a list of dimensions for data breakdown.
+	Metrics      []string `json:"metrics,omitempty" url:"metrics,comma,omitempty"`                           // Metrics is a list of metrics to be fetched.
+	Sort         []string `json:"sort,omitempty" url:"sort,comma,omitempty"`                                 // Sort is a list of fields to sort the data by.
+	Accuracy     string   `json:"accuracy,omitempty" url:"accuracy,omitempty"`                               // Accuracy is a sample size for the report.
+	Limit        int      `json:"limit,omitempty" url:"limit,omitempty"`                                     // Limit is the maximum number of rows to return.
+	Offset       int      `json:"offset,omitempty" url:"offset,omitempty"`                                   // Offset is the offset of the first row to return.
+	Filters      string   `json:"filters,omitempty" url:"filters,omitempty"`                                 // Filters is a filter string to apply to the data.
+	Lang         string   `json:"lang,omitempty" url:"lang,omitempty"`                                       // Lang is the language for the response.
+	Preset       string   `json:"preset,omitempty" url:"preset,omitempty"`                                   // Preset is the preset used for the query.
+	Timezone     string   `json:"timezone,omitempty" url:"timezone,omitempty"`                               // Timezone is the timezone to use for the data.
+	DirectLogins []string `json:"direct_client_logins,omitempty" url:"direct_client_logins,comma,omitempty"` // DirectLogins is a list of direct client logins.
+
+	Currency         string `json:"currency,omitempty" url:"currency,omitempty"`                   // Currency is the ISO 4217 currency code for money metrics.
+	IncludeUndefined bool   `json:"include_undefined,omitempty" url:"include_undefined,omitempty"` // IncludeUndefined includes rows with undefined dimension values.
+	ProposedAccuracy bool   `json:"proposed_accuracy,omitempty" url:"proposed_accuracy,omitempty"` // ProposedAccuracy lets the API choose the accuracy to reduce sampling.
+	Group            string `json:"group,omitempty" url:"group,omitempty"`                         // Group is the time grouping for time dimensions.
+	Quantile         int    `json:"quantile,omitempty" url:"quantile,omitempty"`                   // Quantile is the quantile for quantile metrics in percents.
+	Pretty           bool   `json:"pretty,omitempty" url:"pretty,omitempty"`                       // Pretty requests a formatted response.
+	Attribution      string `json:"attribution,omitempty" url:"attribution,omitempty"`             // Attribution is the attribution model for the <attribution> parametrized fields.
+}
+
+// Response represents the response from a stat table query.
+type Response struct {
+	Query     *Query          `json:"query"`      // Query contains the query parameters used to fetch this data.
+	Data      []ResponseEntry `json:"data"`       // Data contains the actual data returned by the query.
+	TotalRows int             `json:"total_rows"` // TotalRows is the total number of rows matching the query.
+	Totals    []*float64      `json:"totals"`     // Totals is a list of total metric values for all matching rows.
+	Min       []*float64      `json:"min"`        // Min is a list of minimal metric values.
+	Max       []*float64      `json:"max"`        // Max is a list of maximal metric values.
+
+	Sampled               bool    `json:"sampled"`                 // Sampled indicates whether the data is sampled.
+	SampleShare           float64 `json:"sample_share"`            // SampleShare is the share of the data used for the sample.
+	SampleSize            int64   `json:"sample_size"`             // SampleSize is the number of rows in the sample.
+	SampleSpace           int64   `json:"sample_space"`            // SampleSpace is the number of rows in the sample space.
+	DataLag               int     `json:"data_lag"`                // DataLag is the data update delay in seconds.
+	ContainsSensitiveData bool    `json:"contains_sensitive_data"` // ContainsSensitiveData indicates whether some data is hidden for privacy.
+}
+
+// ResponseEntry represents a single row in the stat table data.
+type ResponseEntry struct {
+	Dimensions []struct {
+		Name string `json:"name"`         // Name is the name of the dimension.
+		Id   string `json:"id,omitempty"` // Id is the optional ID of the dimension.
+	} `json:"dimensions"` // Dimensions is a list of dimensions for this row.
+	Metrics []*float64 `json:"metrics"` // Metrics is a list of metrics for this row. A nil value means null.
+}
+
+// CSVResponse represents the response from a stat table query in the CSV format.
+type CSVResponse struct {
+	Query *Query        // Query contains the query parameters used to fetch this data.
+	Body  io.ReadCloser // Body is the raw CSV response body.
+	Rows  int           // Rows is the number of the data records read from the body without the totals record.
+}
+
+// Response formats.
+const (
+	FormatJSON = "json" // FormatJSON reads the report from the data endpoint.
+	FormatCSV  = "csv"  // FormatCSV streams the report from the data.csv endpoint.
+)
+
+// Dimension IDs modes.
+const (
+	DimensionIDsNone   = "none"   // DimensionIDsNone emits dimension names only.
+	DimensionIDsField  = "field"  // DimensionIDsField emits dimension IDs as separate <dimension>_id fields.
+	DimensionIDsObject = "object" // DimensionIDsObject emits dimensions as {name, id} objects.
+)
+
+// Report shapes.
+const (
+	ShapeWide  = "wide"  // ShapeWide emits one message per row with metrics as fields.
+	ShapeLong  = "long"  // ShapeLong emits one message per row and metric with the metric and value fields.
+	ShapeTable = "table" // ShapeTable emits the whole report as one message.
+)
@@ -0,0 +1,425 @@
+package stattable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"golang.org/x/sync/errgroup"
+)
+
+var errSampled = errors.New("sampled data is not allowed")
+
+// ErrReadFailed is wrapped by the errors the report queries are stopped with.
+// The input drops the reader on it, so the report is read again by the next connection.
+var ErrReadFailed = errors.New("report read failed")
+
+// Options controls how the report is split into queries and converted to messages.
+type Options struct {
+	Format           string       // Format is a response format.
+	Batch            BatchOptions // Batch controls how responses are converted to messages.
+	Totals           bool         // Totals emits a separate message with the report totals.
+	FailOnSampled    bool         // FailOnSampled stops reading if the report data is sampled.
+	RequireUnsampled bool         // RequireUnsampled bisects the date range until each sub-report is not sampled.
+	FanOut           bool         // FanOut issues a separate query per counter.
+	Concurrency      int          // Concurrency is the maximum number of concurrent queries.
+	MaxMetrics       int          // MaxMetrics is the maximum number of metrics per query, zero means no limit.
+	SettleDays       int          // SettleDays splits the report into queries per counter and day if positive.
+	Expand           ExpandFunc   // Expand expands parametrized fields if set.
+	Diff             *Differ      // Diff emits only new and changed rows if set.
+}
+
+// Reader reads the report plan queries in the background and returns their pages.
+// The product API client is injected, so the Reader is shared by the Yandex.Metrika
+// and Yandex.AppMetrika stat table inputs.
+type Reader struct {
+	client  Client
+	opts    Options
+	plan    []step
+	results chan result
+	errs    chan error // errs receives the error the plan queries are stopped with.
+	err     error      // err is the error returned by ReadBatch when the results are read.
+	logger  *service.Logger
+	shutSig *shutdown.Signaller
+}
+
+// NewReader creates a Reader of the client reports.
+func NewReader(client Client, opts Options, logger *service.Logger) *Reader {
+	if opts.Diff != nil {
+		opts.Diff.keys = opts.Batch.Keys
+	}
+
+	return &Reader{
+		client:  client,
+		opts:    opts,
+		logger:  logger,
+		shutSig: shutdown.NewSignaller(),
+	}
+}
+
+// Start builds the plan of the query and runs the plan queries in the background with limited concurrency.
+// Pages are sent to the results channel which is closed when all queries are done.
+func (r *Reader) Start(ctx context.Context, query *Query) error {
+	plan, err := r.buildPlan(ctx, *query)
+	if err != nil {
+		return err
+	}
+
+	r.plan = plan
+
+	r.logger.
+		With("queries", len(r.plan)).
+		Debug("report plan is built")
+
+	runCtx, cancel := r.shutSig.SoftStopCtx(context.Background())
+
+	r.results = make(chan result)
+	r.errs = make(chan error, 1)
+
+	go func() {
+		defer cancel()
+		defer close(r.results)
+
+		if err := r.run(runCtx); err != nil && !errors.Is(err, context.Canceled) {
+			r.errs <- err
+		}
+	}()
+
+	return nil
+}
+
+// run reads the plan queries and sends the tombstones in the diff mode.
+func (r *Reader) run(ctx context.Context) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(r.opts.Concurrency, 1))
+
+	for _, s := range r.plan {
+		g.Go(func() error {
+			return r.readStep(gctx, s)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if r.opts.Diff != nil {
+		return r.sendTombstones(ctx)
+	}
+
+	return nil
+}
+
+// sendTombstones sends the rows deleted since the previous poll in the diff mode.
+func (r *Reader) sendTombstones(ctx context.Context) error {
+	msgs, pending, err := r.opts.Diff.Tombstones(ctx)
+	if err != nil {
+		return fmt.Errorf("can't build deleted rows: %w", err)
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	r.logger.
+		With("rows", len(msgs)).
+		Debug("send deleted rows")
+
+	select {
+	case r.results <- result{msgs: msgs, pending: pending}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReadBatch returns the next page of the report or the whole report in the table shape.
+// In the diff mode the cache is updated when the batch is delivered.
+func (r *Reader) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	res, err := r.readPage(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msgs, pending := res.msgs, []*diffPending{res.pending}
+
+	if r.opts.Batch.Shape == ShapeTable {
+		msgs, pending, err = r.readTable(ctx, res)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ack := func(ctx context.Context, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		return r.commit(ctx, pending)
+	}
+
+	return msgs, ack, nil
+}
+
+// commit applies the pending diff cache updates of a delivered batch.
+func (r *Reader) commit(ctx context.Context, pending []*diffPending) error {
+	for _, p := range pending {
+		if p == nil {
+			continue
+		}
+
+		if err := r.opts.Diff.commit(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close stops the background queries.
+func (r *Reader) Close() {
+	r.shutSig.TriggerHardStop()
+}
+
+// readTable reads the rest of the report and merges it into a single message.
+func (r *Reader) readTable(ctx context.Context, first result) (service.MessageBatch, []*diffPending, error) {
+	msgs, pending := first.msgs, []*diffPending{first.pending}
+
+	for {
+		page, err := r.readPage(ctx)
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		msgs = append(msgs, page.msgs...)
+		pending = append(pending, page.pending)
+	}
+
+	msg, err := utils.TableMessage(msgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.MessageBatch{msg}, pending, nil
+}
+
+// result is a page of the report with the pending diff cache update.
+type result struct {
+	msgs    service.MessageBatch
+	pending *diffPending
+}
+
+// readPage reads the next page of the report.
+func (r *Reader) readPage(ctx context.Context) (result, error) {
+	select {
+	case res, ok := <-r.results:
+		if !ok {
+			return result{}, r.finish()
+		}
+
+		return res, nil
+	case <-ctx.Done():
+		return result{}, ctx.Err()
+	}
+}
+
+// finish returns the error the plan queries are stopped with or service.ErrEndOfInput if the report is read.
+// The error is returned by all subsequent calls, so a failed report does not end the input as a complete one.
+func (r *Reader) finish() error {
+	if r.err != nil {
+		return r.err
+	}
+
+	select {
+	case err := <-r.errs:
+		r.err = fmt.Errorf("%w: %w", ErrReadFailed, err)
+
+		r.logger.
+			With("error", err).
+			Error("report read failed")
+
+		return r.err
+	default:
+		return service.ErrEndOfInput
+	}
+}
+
+// readStep reads all pages of the plan query.
+func (r *Reader) readStep(ctx context.Context, s step) error {
+	page := &pageState{}
+
+	for !page.done {
+		s.query.Offset = page.fetched + 1
+
+		r.logger.
+			With("counter_id", s.counter).
+			Info("Fetch Reporting API data")
+
+		var (
+			msgs service.MessageBatch
+			err  error
+		)
+
+		switch r.opts.Format {
+		case FormatCSV:
+			msgs, err = r.readCSV(ctx, s.query, page)
+		default:
+			msgs, err = r.readJSON(ctx, s.query, page)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// an empty query result is skipped
+		if len(msgs) == 0 {
+			continue
+		}
+
+		for _, msg := range msgs {
+			if err := s.setMeta(msg); err != nil {
+				return err
+			}
+		}
+
+		var pending *diffPending
+
+		if r.opts.Diff != nil {
+			msgs, pending, err = r.opts.Diff.Filter(ctx, s, msgs)
+			if err != nil {
+				return err
+			}
+
+			if len(msgs) == 0 {
+				continue
+			}
+		}
+
+		select {
+		case r.results <- result{msgs: msgs, pending: pending}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if s.partition && s.part == 1 && page.fetched == 0 {
+		msg, err := s.emptyMessage()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case r.results <- result{msgs: service.MessageBatch{msg}}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// pageState is a pagination state of a plan query.
+type pageState struct {
+	done    bool
+	fetched int
+	total   int
+}
+
+func (r *Reader) readJSON(ctx context.Context, q *Query, page *pageState) (service.MessageBatch, error) {
+	data, err := r.client.GetWithContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		r.logger.
+			Warn("response return no data")
+
+		page.done = true
+
+		return nil, nil
+	}
+
+	if data.TotalRows == 0 || len(data.Data) == 0 {
+		r.logger.
+			Warn("response return 0 rows")
+
+		page.done = true
+
+		return nil, nil
+	}
+
+	if data.Sampled {
+		r.logger.
+			With(
+				"sample_share", data.SampleShare,
+				"sample_size", data.SampleSize,
+			).
+			Warn("response data is sampled")
+
+		if r.opts.FailOnSampled {
+			return nil, errSampled
+		}
+	}
+
+	first := page.fetched == 0
+
+	if page.total == 0 {
+		page.total = data.TotalRows
+	}
+
+	page.fetched += len(data.Data)
+	page.done = page.total > 0 && page.fetched >= page.total
+
+	msgs, err := data.BatchWithOptions(r.opts.Batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.opts.Totals && first {
+		msg, err := data.TotalsMessage(r.opts.Batch)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// readCSV fetches a page of the report from the CSV endpoint.
+// The CSV response has no total rows counter, so a short page means the end of the report.
+func (r *Reader) readCSV(ctx context.Context, q *Query, page *pageState) (service.MessageBatch, error) {
+	data, err := r.client.GetCSVWithContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := data.BatchWithOptions(r.opts.Batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Rows == 0 {
+		r.logger.
+			Warn("response return 0 rows")
+
+		page.done = true
+
+		return nil, nil
+	}
+
+	// the offset counts the report rows, a row is shaped into several messages in the long shape
+	page.fetched += data.Rows
+	page.done = data.Rows < q.Limit
+
+	return msgs, nil
+}
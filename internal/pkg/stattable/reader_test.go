@@ -0,0 +1,232 @@
+package stattable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient returns the responses of the get functions and records the queries.
+type fakeClient struct {
+	mut     sync.Mutex
+	queries []Query
+	get     func(q *Query) (*Response, error)
+	getCSV  func(q *Query) (*CSVResponse, error)
+}
+
+func (c *fakeClient) GetWithContext(_ context.Context, q *Query) (*Response, error) {
+	c.mut.Lock()
+	c.queries = append(c.queries, *q)
+	c.mut.Unlock()
+
+	return c.get(q)
+}
+
+func (c *fakeClient) GetCSVWithContext(_ context.Context, q *Query) (*CSVResponse, error) {
+	c.mut.Lock()
+	c.queries = append(c.queries, *q)
+	c.mut.Unlock()
+
+	if c.getCSV == nil {
+		return nil, errors.New("not implemented")
+	}
+
+	return c.getCSV(q)
+}
+
+// rowResponse returns a single row response of the query.
+func rowResponse(q *Query, sampled bool) *Response {
+	entry := ResponseEntry{Metrics: []*float64{ptr(1.0)}}
+
+	return &Response{
+		Query:     &Query{IDs: q.IDs, Metrics: []string{"ym:s:visits"}},
+		Data:      []ResponseEntry{entry},
+		TotalRows: 1,
+		Sampled:   sampled,
+	}
+}
+
+func TestReader_ReadBatch(t *testing.T) {
+	apiErr := errors.New("api error")
+
+	testCases := []struct {
+		name          string
+		opts          Options
+		get           func(q *Query) (*Response, error)
+		expectedRows  int
+		expectedError error
+	}{
+		{
+			name:         "Report is read",
+			opts:         Options{Concurrency: 1},
+			get:          func(q *Query) (*Response, error) { return rowResponse(q, false), nil },
+			expectedRows: 1,
+		},
+		{
+			name:          "API error",
+			opts:          Options{Concurrency: 1},
+			get:           func(*Query) (*Response, error) { return nil, apiErr },
+			expectedError: apiErr,
+		},
+		{
+			name:          "Sampled data is not allowed",
+			opts:          Options{Concurrency: 1, FailOnSampled: true},
+			get:           func(q *Query) (*Response, error) { return rowResponse(q, true), nil },
+			expectedError: errSampled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeClient{get: tc.get}
+			reader := NewReader(client, tc.opts, service.MockResources().Logger())
+			defer reader.Close()
+
+			ctx := context.Background()
+
+			require.NoError(t, reader.Start(ctx, &Query{IDs: []int{1}, Metrics: []string{"ym:s:visits"}}))
+
+			rows := 0
+
+			for {
+				msgs, ack, err := reader.ReadBatch(ctx)
+				if errors.Is(err, service.ErrEndOfInput) {
+					break
+				}
+
+				if tc.expectedError != nil {
+					require.ErrorIs(t, err, tc.expectedError)
+					require.ErrorIs(t, err, ErrReadFailed)
+
+					// the error is returned again instead of the end of input
+					_, _, err = reader.ReadBatch(ctx)
+					assert.ErrorIs(t, err, tc.expectedError)
+
+					return
+				}
+
+				require.NoError(t, err)
+				require.NoError(t, ack(ctx, nil))
+
+				rows += len(msgs)
+			}
+
+			assert.Nil(t, tc.expectedError)
+			assert.Equal(t, tc.expectedRows, rows)
+		})
+	}
+}
+
+func TestReader_ReadBatchEmptyPartition(t *testing.T) {
+	client := &fakeClient{
+		get: func(q *Query) (*Response, error) {
+			if q.IDs[0] == 2 {
+				return &Response{Query: q}, nil
+			}
+
+			return rowResponse(q, false), nil
+		},
+	}
+
+	reader := NewReader(client, Options{Concurrency: 1, SettleDays: 1}, service.MockResources().Logger())
+	defer reader.Close()
+
+	ctx := context.Background()
+
+	query := &Query{
+		IDs:     []int{1, 2},
+		Metrics: []string{"ym:s:visits"},
+		Date1:   "2024-01-01",
+		Date2:   "2024-01-01",
+	}
+
+	require.NoError(t, reader.Start(ctx, query))
+
+	rowTypes := map[int]string{}
+
+	for {
+		msgs, _, err := reader.ReadBatch(ctx)
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		for _, msg := range msgs {
+			counter, _ := msg.MetaGetMut("counter_id")
+			rowType, _ := msg.MetaGet("row_type")
+			rowTypes[counter.(int)] = rowType
+
+			partition, _ := msg.MetaGetMut("replace_partition")
+			assert.Equal(t, map[string]any{"counter_id": counter, "date": "2024-01-01"}, partition)
+		}
+	}
+
+	assert.Equal(t, map[int]string{1: "data", 2: "empty"}, rowTypes)
+}
+
+func TestReader_ReadBatchCSVLongShape(t *testing.T) {
+	// pages of the report with the limit of two rows
+	pages := map[int]string{
+		1: "Date,Sessions,Users\nTotal and averages,30,15\n2024-01-01,10,5\n2024-01-02,10,5\n",
+		3: "Date,Sessions,Users\n2024-01-03,10,5\n",
+	}
+
+	client := &fakeClient{
+		getCSV: func(q *Query) (*CSVResponse, error) {
+			body, ok := pages[q.Offset]
+			if !ok {
+				return nil, fmt.Errorf("unexpected offset %d", q.Offset)
+			}
+
+			return &CSVResponse{Query: q, Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+	}
+
+	opts := Options{Format: FormatCSV, Concurrency: 1, Batch: BatchOptions{Shape: ShapeLong}}
+
+	reader := NewReader(client, opts, service.MockResources().Logger())
+	defer reader.Close()
+
+	ctx := context.Background()
+
+	query := &Query{
+		IDs:        []int{1},
+		Dimensions: []string{"ym:s:date"},
+		Metrics:    []string{"ym:s:visits", "ym:s:users"},
+		Limit:      2,
+	}
+
+	require.NoError(t, reader.Start(ctx, query))
+
+	rows := 0
+
+	for {
+		msgs, _, err := reader.ReadBatch(ctx)
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		rows += len(msgs)
+	}
+
+	// a message per row and metric
+	assert.Equal(t, 6, rows)
+
+	offsets := make([]int, 0, len(client.queries))
+	for _, q := range client.queries {
+		offsets = append(offsets, q.Offset)
+	}
+
+	assert.Equal(t, []int{1, 3}, offsets)
+}
@@ -0,0 +1,103 @@
+package stattable
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// dateWindow is a date range of a sub-report.
+type dateWindow struct {
+	Date1 time.Time
+	Date2 time.Time
+}
+
+// newDateWindow creates a dateWindow from dates in YYYY-MM-DD format.
+func newDateWindow(date1, date2 string) (dateWindow, error) {
+	var (
+		w   dateWindow
+		err error
+	)
+
+	w.Date1, err = time.Parse(dateLayout, date1)
+	if err != nil {
+		return w, fmt.Errorf("cannot parse date1 %q: %w", date1, err)
+	}
+
+	w.Date2, err = time.Parse(dateLayout, date2)
+	if err != nil {
+		return w, fmt.Errorf("cannot parse date2 %q: %w", date2, err)
+	}
+
+	if w.Date1.After(w.Date2) {
+		return w, fmt.Errorf("date1 %q is after date2 %q", date1, date2)
+	}
+
+	return w, nil
+}
+
+// Days returns the number of days in the window.
+func (w dateWindow) Days() int {
+	return int(w.Date2.Sub(w.Date1).Hours()/24) + 1
+}
+
+// Split splits the window into two halves.
+func (w dateWindow) Split() (dateWindow, dateWindow) {
+	mid := w.Date1.AddDate(0, 0, w.Days()/2-1)
+
+	return dateWindow{Date1: w.Date1, Date2: mid}, dateWindow{Date1: mid.AddDate(0, 0, 1), Date2: w.Date2}
+}
+
+func (w dateWindow) String() string {
+	return w.Date1.Format(dateLayout) + ".." + w.Date2.Format(dateLayout)
+}
+
+// planWindows bisects the date window until each sub-report is not sampled.
+// A single day window is returned as is even if it is still sampled.
+func (r *Reader) planWindows(ctx context.Context, base Query, w dateWindow) ([]dateWindow, error) {
+	q := base
+	q.Date1 = w.Date1.Format(dateLayout)
+	q.Date2 = w.Date2.Format(dateLayout)
+	q.Limit = 1
+	q.Offset = 1
+
+	r.logger.
+		With("window", w.String()).
+		Debug("probe report sampling")
+
+	data, err := r.client.GetWithContext(ctx, &q)
+	if err != nil {
+		return nil, err
+	}
+
+	if !data.Sampled {
+		return []dateWindow{w}, nil
+	}
+
+	if w.Days() == 1 {
+		r.logger.
+			With(
+				"window", w.String(),
+				"sample_share", data.SampleShare,
+			).
+			Warn("single day report is still sampled")
+
+		return []dateWindow{w}, nil
+	}
+
+	left, right := w.Split()
+
+	leftWindows, err := r.planWindows(ctx, base, left)
+	if err != nil {
+		return nil, err
+	}
+
+	rightWindows, err := r.planWindows(ctx, base, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(leftWindows, rightWindows...), nil
+}
@@ -0,0 +1,140 @@
+package stattable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDateWindow(t *testing.T) {
+	testCases := []struct {
+		name         string
+		date1        string
+		date2        string
+		expectedDays int
+		expectError  bool
+	}{
+		{name: "Single day", date1: "2024-01-01", date2: "2024-01-01", expectedDays: 1},
+		{name: "Month", date1: "2024-01-01", date2: "2024-01-31", expectedDays: 31},
+		{name: "Leap year", date1: "2024-02-01", date2: "2024-03-01", expectedDays: 30},
+		{name: "Reversed dates", date1: "2024-01-02", date2: "2024-01-01", expectError: true},
+		{name: "Invalid date", date1: "today", date2: "2024-01-01", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := newDateWindow(tc.date1, tc.date2)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDays, w.Days())
+		})
+	}
+}
+
+func TestDateWindow_Split(t *testing.T) {
+	testCases := []struct {
+		name          string
+		date1         string
+		date2         string
+		expectedLeft  string
+		expectedRight string
+	}{
+		{name: "Two days", date1: "2024-01-01", date2: "2024-01-02", expectedLeft: "2024-01-01..2024-01-01", expectedRight: "2024-01-02..2024-01-02"},
+		{name: "Odd days", date1: "2024-01-01", date2: "2024-01-05", expectedLeft: "2024-01-01..2024-01-02", expectedRight: "2024-01-03..2024-01-05"},
+		{name: "Even days", date1: "2024-01-01", date2: "2024-01-04", expectedLeft: "2024-01-01..2024-01-02", expectedRight: "2024-01-03..2024-01-04"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := newDateWindow(tc.date1, tc.date2)
+			require.NoError(t, err)
+
+			left, right := w.Split()
+			assert.Equal(t, tc.expectedLeft, left.String())
+			assert.Equal(t, tc.expectedRight, right.String())
+			assert.Equal(t, w.Days(), left.Days()+right.Days())
+		})
+	}
+}
+
+func TestReader_PlanWindows(t *testing.T) {
+	testCases := []struct {
+		name            string
+		maxDays         int // maxDays is the longest unsampled window
+		date1           string
+		date2           string
+		expectedWindows []string
+	}{
+		{
+			name:            "Unsampled report",
+			maxDays:         31,
+			date1:           "2024-01-01",
+			date2:           "2024-01-31",
+			expectedWindows: []string{"2024-01-01..2024-01-31"},
+		},
+		{
+			name:    "Sampled report",
+			maxDays: 2,
+			date1:   "2024-01-01",
+			date2:   "2024-01-08",
+			expectedWindows: []string{
+				"2024-01-01..2024-01-02",
+				"2024-01-03..2024-01-04",
+				"2024-01-05..2024-01-06",
+				"2024-01-07..2024-01-08",
+			},
+		},
+		{
+			name:    "Sampled single days",
+			maxDays: 0,
+			date1:   "2024-01-01",
+			date2:   "2024-01-02",
+			expectedWindows: []string{
+				"2024-01-01..2024-01-01",
+				"2024-01-02..2024-01-02",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeClient{
+				get: func(q *Query) (*Response, error) {
+					w, err := newDateWindow(q.Date1, q.Date2)
+					if err != nil {
+						return nil, err
+					}
+
+					return &Response{Sampled: w.Days() > tc.maxDays}, nil
+				},
+			}
+
+			reader := NewReader(client, Options{}, service.MockResources().Logger())
+
+			w, err := newDateWindow(tc.date1, tc.date2)
+			require.NoError(t, err)
+
+			windows, err := reader.planWindows(context.Background(), Query{IDs: []int{1}}, w)
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(windows))
+			for _, w := range windows {
+				actual = append(actual, w.String())
+			}
+
+			assert.Equal(t, tc.expectedWindows, actual)
+
+			for _, q := range client.queries {
+				assert.Equal(t, 1, q.Limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package upload
+
+import (
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// description documents the per counter uploads, the retries and the sync responses of the upload outputs.
+const description = `
+
+### Uploads
+
+The messages are uploaded per counter. A failed upload of a counter fails only the messages of the counter.
+A retry of the batch within an hour does not upload the counters which are already uploaded by a previous attempt, the skipped uploads are logged.
+
+The upload identifiers and statuses are logged. Metadata set by an output is not visible to other components,
+so the messages are not changed. Enable the ` + "`sync_response`" + ` field to add the uploaded messages with the
+` + "`upload_id` and `upload_status`" + ` metadata fields to the synchronous response of the input,
+e.g. to return them by the ` + "`http_server`" + ` input for auditing. Rejected rows are not added.`
+
+// Description appends the upload docs to the description of an upload output.
+func Description(desc string) string {
+	return desc + description
+}
+
+// SyncResponseField returns the sync_response config field of the upload outputs.
+func SyncResponseField() *service.ConfigField {
+	return service.NewBoolField("sync_response").
+		Description("Add the uploaded messages with the `upload_id` and `upload_status` metadata fields to the synchronous response of the input.").
+		Default(false)
+}
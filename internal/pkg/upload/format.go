@@ -0,0 +1,59 @@
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// FormatValue converts a structured message value into a CSV cell.
+func FormatValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case int:
+		return strconv.Itoa(t)
+	case time.Time:
+		return strconv.FormatInt(t.Unix(), 10)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// FormatTime converts a Unix timestamp, a time or an RFC 3339 string into a Unix timestamp string.
+func FormatTime(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return s, nil
+		}
+
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatInt(t.Unix(), 10), nil
+	}
+
+	s := FormatValue(v)
+	if s == "" {
+		return "", errors.New("value is required")
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(int64(f), 10), nil
+}
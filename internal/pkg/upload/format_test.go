@@ -0,0 +1,66 @@
+package upload
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "Nil", value: nil, expected: ""},
+		{name: "String", value: "a,b", expected: "a,b"},
+		{name: "Number", value: json.Number("12.50"), expected: "12.50"},
+		{name: "Float", value: 12.5, expected: "12.5"},
+		{name: "Large float", value: 1234567890123.0, expected: "1234567890123"},
+		{name: "Int64", value: int64(42), expected: "42"},
+		{name: "Int", value: 42, expected: "42"},
+		{name: "Bool", value: true, expected: "true"},
+		{name: "Time", value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), expected: "1704164645"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FormatValue(tc.value))
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       any
+		expected    string
+		expectError bool
+	}{
+		{name: "Unix string", value: "1704164645", expected: "1704164645"},
+		{name: "Unix number", value: 1704164645.0, expected: "1704164645"},
+		{name: "Fractional Unix number", value: 1704164645.9, expected: "1704164645"},
+		{name: "JSON number", value: json.Number("1704164645"), expected: "1704164645"},
+		{name: "RFC 3339", value: "2024-01-02T06:04:05+03:00", expected: "1704164645"},
+		{name: "Time", value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), expected: "1704164645"},
+		{name: "Date only", value: "2024-01-02", expectError: true},
+		{name: "Missing", value: nil, expectError: true},
+		{name: "Not a number", value: true, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := FormatTime(tc.value)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package upload
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// Group is the batch indexes of the messages of a counter.
+type Group struct {
+	Counter int
+	Indexes []int
+}
+
+// Messages returns the messages of the group.
+func (g Group) Messages(batch service.MessageBatch) service.MessageBatch {
+	msgs := make(service.MessageBatch, 0, len(g.Indexes))
+	for _, i := range g.Indexes {
+		msgs = append(msgs, batch[i])
+	}
+
+	return msgs
+}
+
+// GroupByCounter groups the batch messages by the interpolated counter identifier in the order of
+// the first appearance. Messages with an invalid counter identifier are failed in the errors.
+func GroupByCounter(batch service.MessageBatch, counter *service.InterpolatedString, errs *Errors) []Group {
+	var groups []Group
+
+	// group positions by the counter
+	positions := map[int]int{}
+
+	for i := range batch {
+		raw, err := batch.TryInterpolatedString(i, counter)
+		if err != nil {
+			errs.Fail(fmt.Errorf("counter_id interpolation: %w", err), i)
+
+			continue
+		}
+
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			errs.Fail(fmt.Errorf("invalid counter_id %q: %w", raw, err), i)
+
+			continue
+		}
+
+		p, ok := positions[id]
+		if !ok {
+			p = len(groups)
+			positions[id] = p
+			groups = append(groups, Group{Counter: id})
+		}
+
+		groups[p].Indexes = append(groups[p].Indexes, i)
+	}
+
+	return groups
+}
+
+// Errors collects the errors of the failed batch messages, so only they are retried.
+type Errors struct {
+	batch service.MessageBatch
+	err   *service.BatchError
+}
+
+// NewErrors creates the errors of the batch.
+func NewErrors(batch service.MessageBatch) *Errors {
+	return &Errors{batch: batch}
+}
+
+// Fail marks the messages of the indexes as failed with the error.
+func (e *Errors) Fail(err error, indexes ...int) {
+	if e.err == nil {
+		e.err = service.NewBatchError(e.batch, err)
+	}
+
+	for _, i := range indexes {
+		e.err.Failed(i, err)
+	}
+}
+
+// Err returns the batch error or nil if no messages are failed.
+func (e *Errors) Err() error {
+	if e.err == nil {
+		return nil
+	}
+
+	return e.err
+}
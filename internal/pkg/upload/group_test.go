@@ -0,0 +1,66 @@
+package upload
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failedIndexes returns the indexes of the failed messages of the batch error.
+func failedIndexes(t *testing.T, index *service.Indexer, err error) []int {
+	t.Helper()
+
+	var batchErr *service.BatchError
+
+	require.ErrorAs(t, err, &batchErr)
+
+	var indexes []int
+
+	batchErr.WalkMessagesIndexedBy(index, func(i int, _ *service.Message, err error) bool {
+		if err != nil {
+			indexes = append(indexes, i)
+		}
+
+		return true
+	})
+
+	return indexes
+}
+
+func TestGroupByCounter(t *testing.T) {
+	counter, err := service.NewInterpolatedString(`${! meta("counter_id") }`)
+	require.NoError(t, err)
+
+	batch := service.MessageBatch{}
+
+	for _, id := range []string{"2", "1", "2", "x", "1"} {
+		msg := service.NewMessage(nil)
+		msg.MetaSetMut("counter_id", id)
+		batch = append(batch, msg)
+	}
+
+	index := batch.Index()
+	errs := NewErrors(batch)
+
+	groups := GroupByCounter(batch, counter, errs)
+	assert.Equal(t, []Group{{Counter: 2, Indexes: []int{0, 2}}, {Counter: 1, Indexes: []int{1, 4}}}, groups)
+	assert.Equal(t, service.MessageBatch{batch[1], batch[4]}, groups[1].Messages(batch))
+	assert.Equal(t, []int{3}, failedIndexes(t, index, errs.Err()))
+}
+
+func TestErrors(t *testing.T) {
+	batch := service.MessageBatch{service.NewMessage(nil), service.NewMessage(nil), service.NewMessage(nil)}
+
+	index := batch.Index()
+	errs := NewErrors(batch)
+	assert.NoError(t, errs.Err())
+
+	errs.Fail(errors.New("first"), 0, 2)
+	errs.Fail(errors.New("second"), 1)
+
+	assert.EqualError(t, errs.Err(), "first")
+	assert.Equal(t, []int{0, 1, 2}, failedIndexes(t, index, errs.Err()))
+}
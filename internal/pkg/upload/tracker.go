@@ -0,0 +1,86 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TrackerTTL is the time a finished upload of a batch which is not delivered yet is remembered.
+// It bounds the tracker when a partially failed batch is never retried.
+const TrackerTTL = time.Hour
+
+// Tracker remembers the finished uploads of the batches which are not delivered yet,
+// so a retried batch does not post the same data of a counter again.
+type Tracker struct {
+	mut  sync.Mutex
+	ttl  time.Duration
+	now  func() time.Time
+	done map[string]trackerEntry
+}
+
+// trackerEntry is a finished upload with its expiration time.
+type trackerEntry struct {
+	result  Result
+	expires time.Time
+}
+
+// NewTracker creates an empty tracker which forgets the uploads after the ttl.
+func NewTracker(ttl time.Duration) *Tracker {
+	return &Tracker{
+		ttl:  ttl,
+		now:  time.Now,
+		done: map[string]trackerEntry{},
+	}
+}
+
+// Key returns the key of the uploaded data of the counter.
+func Key(counter int, data []byte) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.Itoa(counter)))
+	h.Write([]byte{0})
+	h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Done returns the result of the finished upload of the key.
+func (t *Tracker) Done(key string) (Result, bool) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	e, ok := t.done[key]
+	if !ok || !t.now().Before(e.expires) {
+		return Result{}, false
+	}
+
+	return e.result, true
+}
+
+// Add marks the upload of the key as finished with the result and drops the expired uploads.
+func (t *Tracker) Add(key string, result Result) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	now := t.now()
+
+	for k, e := range t.done {
+		if !now.Before(e.expires) {
+			delete(t.done, k)
+		}
+	}
+
+	t.done[key] = trackerEntry{result: result, expires: now.Add(t.ttl)}
+}
+
+// Forget removes the keys of a delivered batch.
+func (t *Tracker) Forget(keys ...string) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	for _, k := range keys {
+		delete(t.done, k)
+	}
+}
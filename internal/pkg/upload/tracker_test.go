@@ -0,0 +1,59 @@
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	data := []byte("ClientId,Target\n1,goal\n")
+
+	assert.Equal(t, Key(1, data), Key(1, data))
+	assert.NotEqual(t, Key(1, data), Key(2, data))
+	assert.NotEqual(t, Key(1, data), Key(1, []byte("ClientId,Target\n")))
+
+	tracker := NewTracker(time.Hour)
+
+	_, ok := tracker.Done(Key(1, data))
+	assert.False(t, ok)
+
+	tracker.Add(Key(1, data), Result{ID: "10", Status: "UPLOADED"})
+
+	res, ok := tracker.Done(Key(1, data))
+	assert.True(t, ok)
+	assert.Equal(t, Result{ID: "10", Status: "UPLOADED"}, res)
+
+	_, ok = tracker.Done(Key(2, data))
+	assert.False(t, ok)
+
+	tracker.Forget(Key(1, data), Key(2, data))
+
+	_, ok = tracker.Done(Key(1, data))
+	assert.False(t, ok)
+}
+
+func TestTracker_TTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tracker := NewTracker(time.Hour)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Add("a", Result{ID: "1"})
+
+	now = now.Add(30 * time.Minute)
+	tracker.Add("b", Result{ID: "2"})
+
+	_, ok := tracker.Done("a")
+	assert.True(t, ok)
+
+	now = now.Add(30 * time.Minute)
+
+	_, ok = tracker.Done("a")
+	assert.False(t, ok)
+
+	// the expired uploads are dropped on add
+	tracker.Add("c", Result{ID: "3"})
+	assert.Len(t, tracker.done, 2)
+}
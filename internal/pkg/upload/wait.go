@@ -0,0 +1,39 @@
+package upload
+
+import (
+	"context"
+	"time"
+)
+
+// Wait polls the status with the get function every interval until the done function reports true.
+// It also reports whether the processing is finished before the timeout.
+func Wait[T any](
+	ctx context.Context,
+	interval, timeout time.Duration,
+	status T,
+	done func(T) bool,
+	get func(context.Context) (T, error),
+) (T, bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	for !done(status) {
+		if time.Now().After(deadline) {
+			return status, false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, false, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		s, err := get(ctx)
+		if err != nil {
+			return status, false, err
+		}
+
+		status = s
+	}
+
+	return status, true, nil
+}
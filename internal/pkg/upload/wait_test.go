@@ -0,0 +1,84 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	done := func(s string) bool { return s == "PROCESSED" }
+
+	// statuses returns the get function which reports the statuses one by one.
+	statuses := func(values ...string) func(context.Context) (string, error) {
+		return func(context.Context) (string, error) {
+			v := values[0]
+			if len(values) > 1 {
+				values = values[1:]
+			}
+
+			return v, nil
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		timeout          time.Duration
+		status           string
+		get              func(context.Context) (string, error)
+		expectedStatus   string
+		expectedFinished bool
+		expectError      bool
+	}{
+		{
+			name:             "Already done",
+			timeout:          time.Second,
+			status:           "PROCESSED",
+			get:              statuses("UPLOADED"),
+			expectedStatus:   "PROCESSED",
+			expectedFinished: true,
+		},
+		{
+			name:             "Processed",
+			timeout:          time.Second,
+			status:           "UPLOADED",
+			get:              statuses("UPLOADED", "PROCESSED"),
+			expectedStatus:   "PROCESSED",
+			expectedFinished: true,
+		},
+		{
+			name:           "Timeout",
+			timeout:        0,
+			status:         "UPLOADED",
+			get:            statuses("PROCESSED"),
+			expectedStatus: "UPLOADED",
+		},
+		{
+			name:    "Status error",
+			timeout: time.Second,
+			status:  "UPLOADED",
+			get: func(context.Context) (string, error) {
+				return "", errors.New("api error")
+			},
+			expectedStatus: "UPLOADED",
+			expectError:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, finished, err := Wait(context.Background(), time.Millisecond, tc.timeout, tc.status, done, tc.get)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedFinished, finished)
+		})
+	}
+}
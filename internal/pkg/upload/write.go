@@ -0,0 +1,114 @@
+package upload
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// Result is the result of an upload of a counter.
+type Result struct {
+	ID     string        // ID is the upload identifier.
+	Status string        // Status is the upload status.
+	Failed map[int]error // Failed maps the message positions of the counter to the errors of the rejected rows.
+}
+
+// Writer uploads the batch messages per counter.
+type Writer struct {
+	Counter      *service.InterpolatedString // Counter is the counter identifier of a message.
+	Uploads      *Tracker                    // Uploads skips the counters uploaded by a previous attempt of the batch.
+	SyncResponse bool                        // SyncResponse adds the uploaded messages with the upload metadata to the sync response.
+	Name         string                      // Name is the name of the uploaded data in the log messages, e.g. "calls".
+	Logger       *service.Logger
+}
+
+// WriteBatch groups the batch messages by counter, encodes the messages of a counter with the encode
+// function and posts them with the post function unless the same data of the counter is uploaded by
+// a previous attempt of the batch. A failed counter fails only its messages. The value returned by
+// the encode function is passed to the post function.
+func WriteBatch[T any](
+	ctx context.Context,
+	w *Writer,
+	batch service.MessageBatch,
+	encode func(msgs service.MessageBatch) (T, []byte, error),
+	post func(ctx context.Context, counter int, msgs service.MessageBatch, v T, data []byte) (Result, error),
+) error {
+	errs := NewErrors(batch)
+
+	var keys []string
+
+	for _, g := range GroupByCounter(batch, w.Counter, errs) {
+		msgs := g.Messages(batch)
+
+		v, data, err := encode(msgs)
+		if err != nil {
+			errs.Fail(err, g.Indexes...)
+
+			continue
+		}
+
+		key := Key(g.Counter, data)
+		keys = append(keys, key)
+
+		// the counter is uploaded by a previous attempt of the batch,
+		// its rejected rows are already reported
+		res, ok := w.Uploads.Done(key)
+		if ok {
+			w.Logger.
+				With("counter_id", g.Counter, "upload_id", res.ID, "rows", len(msgs)).
+				Info(w.Name + " upload is already finished")
+		} else {
+			res, err = post(ctx, g.Counter, msgs, v, data)
+			if err != nil {
+				errs.Fail(err, g.Indexes...)
+
+				continue
+			}
+
+			w.Uploads.Add(key, res)
+
+			for p, err := range res.Failed {
+				errs.Fail(err, g.Indexes[p])
+			}
+		}
+
+		if w.SyncResponse {
+			w.syncResponse(g.Counter, msgs, res)
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	w.Uploads.Forget(keys...)
+
+	return nil
+}
+
+// syncResponse adds the copies of the uploaded messages with the `upload_id` and `upload_status`
+// metadata fields to the sync response. The rejected rows are not added.
+func (w *Writer) syncResponse(counter int, msgs service.MessageBatch, res Result) {
+	resp := make(service.MessageBatch, 0, len(msgs))
+
+	for p, msg := range msgs {
+		if _, ok := res.Failed[p]; ok {
+			continue
+		}
+
+		msg = msg.Copy()
+		msg.MetaSetMut("upload_id", res.ID)
+		msg.MetaSetMut("upload_status", res.Status)
+		resp = append(resp, msg)
+	}
+
+	if len(resp) == 0 {
+		return
+	}
+
+	if err := resp.AddSyncResponse(); err != nil {
+		w.Logger.
+			With("counter_id", counter, "upload_id", res.ID, "error", err).
+			Warn("can't add the upload to the sync response")
+	}
+}
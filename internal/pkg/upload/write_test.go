@@ -0,0 +1,141 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBatch returns the messages of the counters with the counter_id metadata.
+func testBatch(counters ...string) service.MessageBatch {
+	batch := make(service.MessageBatch, len(counters))
+
+	for i, id := range counters {
+		batch[i] = service.NewMessage([]byte(id + "-" + string(rune('a'+i))))
+		batch[i].MetaSetMut("counter_id", id)
+	}
+
+	return batch
+}
+
+// testEncode joins the message contents.
+func testEncode(msgs service.MessageBatch) (int, []byte, error) {
+	parts := make([]string, len(msgs))
+
+	for i, msg := range msgs {
+		b, err := msg.AsBytes()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		parts[i] = string(b)
+	}
+
+	return len(msgs), []byte(strings.Join(parts, "\n")), nil
+}
+
+func testWriter(t *testing.T) *Writer {
+	t.Helper()
+
+	counter, err := service.NewInterpolatedString(`${! meta("counter_id") }`)
+	require.NoError(t, err)
+
+	return &Writer{
+		Counter: counter,
+		Uploads: NewTracker(time.Hour),
+		Name:    "rows",
+		Logger:  service.MockResources().Logger(),
+	}
+}
+
+func TestWriteBatch_Retry(t *testing.T) {
+	w := testWriter(t)
+	batch := testBatch("1", "2", "1")
+
+	var posted []int
+
+	failed := map[int]bool{2: true}
+
+	post := func(ctx context.Context, counter int, msgs service.MessageBatch, rows int, data []byte) (Result, error) {
+		posted = append(posted, counter)
+
+		if failed[counter] {
+			return Result{}, errors.New("upload failed")
+		}
+
+		assert.Equal(t, len(msgs), rows)
+
+		return Result{ID: "10", Status: "UPLOADED"}, nil
+	}
+
+	index := batch.Index()
+
+	err := WriteBatch(context.Background(), w, batch, testEncode, post)
+	assert.Equal(t, []int{1}, failedIndexes(t, index, err))
+	assert.Equal(t, []int{1, 2}, posted)
+	assert.Len(t, w.Uploads.done, 1)
+
+	// the retry does not upload the first counter again
+	posted = nil
+	failed = nil
+
+	require.NoError(t, WriteBatch(context.Background(), w, batch, testEncode, post))
+	assert.Equal(t, []int{2}, posted)
+	assert.Empty(t, w.Uploads.done)
+}
+
+func TestWriteBatch_RejectedRows(t *testing.T) {
+	w := testWriter(t)
+	batch := testBatch("1", "2", "1")
+
+	post := func(ctx context.Context, counter int, msgs service.MessageBatch, rows int, data []byte) (Result, error) {
+		if counter == 1 {
+			return Result{ID: "10", Failed: map[int]error{1: errors.New("invalid row")}}, nil
+		}
+
+		return Result{ID: "11"}, nil
+	}
+
+	index := batch.Index()
+
+	err := WriteBatch(context.Background(), w, batch, testEncode, post)
+	assert.Equal(t, []int{2}, failedIndexes(t, index, err))
+}
+
+func TestWriteBatch_SyncResponse(t *testing.T) {
+	w := testWriter(t)
+	w.SyncResponse = true
+
+	batch, store := testBatch("1", "2").WithSyncResponseStore()
+
+	post := func(ctx context.Context, counter int, msgs service.MessageBatch, rows int, data []byte) (Result, error) {
+		if counter == 2 {
+			return Result{ID: "11", Status: "UPLOADED", Failed: map[int]error{0: errors.New("invalid row")}}, nil
+		}
+
+		return Result{ID: "10", Status: "UPLOADED"}, nil
+	}
+
+	err := WriteBatch(context.Background(), w, batch, testEncode, post)
+	require.Error(t, err)
+
+	resp := store.Read()
+	require.Len(t, resp, 1)
+	require.Len(t, resp[0], 1)
+
+	b, err := resp[0][0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "1-a", string(b))
+
+	id, _ := resp[0][0].MetaGetMut("upload_id")
+	assert.Equal(t, "10", id)
+
+	status, _ := resp[0][0].MetaGetMut("upload_status")
+	assert.Equal(t, "UPLOADED", status)
+}
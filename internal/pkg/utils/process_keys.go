@@ -6,9 +6,48 @@ import (
 	"github.com/iancoleman/strcase"
 )
 
+// Key strategies.
+const (
+	KeyStrategyRaw      = "raw"      // KeyStrategyRaw keeps API field names as is, e.g. `ym:s:lastTrafficSource`.
+	KeyStrategyStripped = "stripped" // KeyStrategyStripped removes the namespace prefix, e.g. `lastTrafficSource`.
+	KeyStrategySnake    = "snake"    // KeyStrategySnake removes the namespace prefix and converts to snake case, e.g. `last_traffic_source`.
+	KeyStrategyAlias    = "alias"    // KeyStrategyAlias uses the aliases only and keeps other names as is.
+)
+
+// namespaceRe matches Metrika and AppMetrika namespace prefixes, e.g. `ym:s:`, `ym:ge:` or `ym:ce2:`.
+var namespaceRe = regexp.MustCompile(`^ym:[a-z0-9]+:`)
+
+// KeyMapper converts API field names to message keys.
+// The zero value uses the snake strategy.
+type KeyMapper struct {
+	Strategy string            // Strategy is one of the key strategies.
+	Aliases  map[string]string // Aliases maps API field names to custom keys. Aliases take precedence over the strategy.
+}
+
+// Key returns the message key of the API field name.
+func (m KeyMapper) Key(k string) string {
+	if alias, ok := m.Aliases[k]; ok {
+		return alias
+	}
+
+	switch m.Strategy {
+	case KeyStrategyRaw, KeyStrategyAlias:
+		return k
+	case KeyStrategyStripped:
+		return StripKey(k)
+	default:
+		return ProcessKey(k)
+	}
+}
+
+// StripKey removes the namespace prefix from the API field name.
+func StripKey(k string) string {
+	return namespaceRe.ReplaceAllString(k, "")
+}
+
+// ProcessKey removes the namespace prefix from the API field name and converts it to snake case.
 func ProcessKey(k string) string {
-	k = regexp.MustCompile("^ym:.*:").
-		ReplaceAllString(k, "")
+	k = StripKey(k)
 
 	// snake case exclusions
 	switch k {
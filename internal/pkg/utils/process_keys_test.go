@@ -17,6 +17,11 @@ func TestProcessKey(t *testing.T) {
 			input:    "ym:some:key",
 			expected: "key",
 		},
+		{
+			name:     "remove AppMetrika prefix",
+			input:    "ym:ce2:eventLabel",
+			expected: "event_label",
+		},
 		{
 			name:     "convert camelCase to snake_case",
 			input:    "someKeyName",
@@ -45,3 +50,28 @@ func TestProcessKey(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyMapper(t *testing.T) {
+	aliases := map[string]string{"ym:ge:users": "app_users"}
+
+	tests := []struct {
+		name     string
+		mapper   KeyMapper
+		input    string
+		expected string
+	}{
+		{name: "default", mapper: KeyMapper{}, input: "ym:s:lastTrafficSource", expected: "last_traffic_source"},
+		{name: "raw", mapper: KeyMapper{Strategy: KeyStrategyRaw}, input: "ym:ge:users", expected: "ym:ge:users"},
+		{name: "stripped", mapper: KeyMapper{Strategy: KeyStrategyStripped}, input: "ym:i:installDevices", expected: "installDevices"},
+		{name: "snake", mapper: KeyMapper{Strategy: KeyStrategySnake}, input: "ym:ce:eventLabel", expected: "event_label"},
+		{name: "alias", mapper: KeyMapper{Strategy: KeyStrategyAlias, Aliases: aliases}, input: "ym:ge:users", expected: "app_users"},
+		{name: "alias fallback", mapper: KeyMapper{Strategy: KeyStrategyAlias, Aliases: aliases}, input: "ym:ge:date", expected: "ym:ge:date"},
+		{name: "alias over snake", mapper: KeyMapper{Strategy: KeyStrategySnake, Aliases: aliases}, input: "ym:ge:users", expected: "app_users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.mapper.Key(tt.input))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// SettleDate moves the date in YYYY-MM-DD format back by the number of settle days.
+func SettleDate(date string, days int) (string, error) {
+	d, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse %q: invalid date format (YYYY-MM-DD)", date)
+	}
+
+	return d.AddDate(0, 0, -days).Format(dateLayout), nil
+}
+
+// DateRange returns all days from date1 to date2 inclusive in YYYY-MM-DD format.
+func DateRange(date1, date2 string) ([]string, error) {
+	d1, err := time.Parse(dateLayout, date1)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: invalid date format (YYYY-MM-DD)", date1)
+	}
+
+	d2, err := time.Parse(dateLayout, date2)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: invalid date format (YYYY-MM-DD)", date2)
+	}
+
+	if d1.After(d2) {
+		return nil, fmt.Errorf("date1 %q is after date2 %q", date1, date2)
+	}
+
+	var days []string
+
+	for d := d1; !d.After(d2); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(dateLayout))
+	}
+
+	return days, nil
+}
+
+// ReplacePartition returns the `replace_partition` metadata value of the counter and date.
+// Sinks can delete and insert the partition atomically.
+func ReplacePartition(counter int, date string) map[string]any {
+	return map[string]any{
+		"counter_id": counter,
+		"date":       date,
+	}
+}
+
+// EmptyPartitionMessage returns the marker of a partition without rows: an empty object with
+// the `row_type` metadata field set to `empty`, so sinks can delete the stale rows of the partition.
+func EmptyPartitionMessage(counter int, date string) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(map[string]any{})
+	msg.MetaSetMut("row_type", "empty")
+	msg.MetaSetMut("replace_partition", ReplacePartition(counter, date))
+
+	return msg
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettleDate(t *testing.T) {
+	got, err := SettleDate("2024-03-02", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-02-28", got)
+
+	got, err = SettleDate("2024-03-02", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-02", got)
+
+	_, err = SettleDate("yesterday", 1)
+	assert.Error(t, err)
+}
+
+func TestDateRange(t *testing.T) {
+	got, err := DateRange("2024-02-28", "2024-03-01")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-02-28", "2024-02-29", "2024-03-01"}, got)
+
+	got, err = DateRange("2024-03-01", "2024-03-01")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-03-01"}, got)
+
+	_, err = DateRange("2024-03-02", "2024-03-01")
+	assert.Error(t, err)
+
+	_, err = DateRange("2024-03-01", "today")
+	assert.Error(t, err)
+}
+
+func TestReplacePartition(t *testing.T) {
+	assert.Equal(t, map[string]any{"counter_id": 1, "date": "2024-03-01"}, ReplacePartition(1, "2024-03-01"))
+}
+
+func TestEmptyPartitionMessage(t *testing.T) {
+	msg := EmptyPartitionMessage(44147844, "2024-03-01")
+
+	v, err := msg.AsStructured()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{}, v)
+
+	rowType, _ := msg.MetaGet("row_type")
+	assert.Equal(t, "empty", rowType)
+
+	partition, _ := msg.MetaGetMut("replace_partition")
+	assert.Equal(t, map[string]any{"counter_id": 44147844, "date": "2024-03-01"}, partition)
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// LongRows converts a wide row to the long format: one row per metric
+// with the "metric" and "value" fields and all non-metric fields of the row.
+func LongRows(row map[string]any, metrics []string) []map[string]any {
+	isMetric := make(map[string]bool, len(metrics))
+	for _, k := range metrics {
+		isMetric[k] = true
+	}
+
+	rows := make([]map[string]any, 0, len(metrics))
+
+	for _, k := range metrics {
+		r := make(map[string]any, len(row)-len(metrics)+2)
+
+		for rk, rv := range row {
+			if !isMetric[rk] {
+				r[rk] = rv
+			}
+		}
+
+		r["metric"] = k
+		r["value"] = row[k]
+
+		rows = append(rows, r)
+	}
+
+	return rows
+}
+
+// TableMessage merges structured messages into a single message
+// with the "rows" array. Messages with the "row_type" metadata field
+// set to "totals" are stored to the "totals" field instead,
+// empty partition markers are skipped.
+// Metadata is copied from the first data message.
+func TableMessage(batch service.MessageBatch) (*service.Message, error) {
+	var (
+		meta   *service.Message
+		totals any
+	)
+
+	rows := make([]any, 0, len(batch))
+
+	for _, msg := range batch {
+		row, err := msg.AsStructuredMut()
+		if err != nil {
+			return nil, err
+		}
+
+		rowType, _ := msg.MetaGet("row_type")
+
+		switch rowType {
+		case "totals":
+			totals = row
+
+			continue
+		case "empty":
+			continue
+		}
+
+		if meta == nil {
+			meta = msg
+		}
+
+		rows = append(rows, row)
+	}
+
+	table := map[string]any{"rows": rows}
+	if totals != nil {
+		table["totals"] = totals
+	}
+
+	msg := service.NewMessage(nil)
+	if meta != nil {
+		msg = meta.Copy()
+	}
+
+	msg.SetStructuredMut(table)
+	msg.MetaSetMut("row_type", "table")
+	msg.MetaSetMut("rows", len(rows))
+
+	return msg, nil
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongRows(t *testing.T) {
+	row := map[string]any{
+		"date":      "2023-10-26",
+		"visits":    100.0,
+		"pageviews": 200.0,
+	}
+
+	expected := []map[string]any{
+		{"date": "2023-10-26", "metric": "visits", "value": 100.0},
+		{"date": "2023-10-26", "metric": "pageviews", "value": 200.0},
+	}
+
+	assert.Equal(t, expected, LongRows(row, []string{"visits", "pageviews"}))
+	assert.Empty(t, LongRows(row, nil))
+}
+
+func TestTableMessage(t *testing.T) {
+	t.Run("rows and totals", func(t *testing.T) {
+		msg1 := service.NewMessage(nil)
+		msg1.SetStructuredMut(map[string]any{"date": "2023-10-26", "visits": 100.0})
+		msg1.MetaSetMut("row_type", "data")
+		msg1.MetaSetMut("total", 2)
+
+		msg2 := service.NewMessage(nil)
+		msg2.SetStructuredMut(map[string]any{"date": "2023-10-27", "visits": 150.0})
+		msg2.MetaSetMut("row_type", "data")
+
+		totals := service.NewMessage(nil)
+		totals.SetStructuredMut(map[string]any{"visits": 250.0})
+		totals.MetaSetMut("row_type", "totals")
+
+		msg, err := TableMessage(service.MessageBatch{msg1, totals, msg2})
+		assert.NoError(t, err)
+
+		realMsg, err := msg.AsStructured()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"rows": []any{
+				map[string]any{"date": "2023-10-26", "visits": 100.0},
+				map[string]any{"date": "2023-10-27", "visits": 150.0},
+			},
+			"totals": map[string]any{"visits": 250.0},
+		}, realMsg)
+
+		rowType, _ := msg.MetaGet("row_type")
+		assert.Equal(t, "table", rowType)
+
+		total, _ := msg.MetaGetMut("total")
+		assert.Equal(t, 2, total)
+
+		rows, _ := msg.MetaGetMut("rows")
+		assert.Equal(t, 2, rows)
+	})
+
+	t.Run("empty partition markers", func(t *testing.T) {
+		msg1 := service.NewMessage(nil)
+		msg1.SetStructuredMut(map[string]any{"date": "2023-10-26", "visits": 100.0})
+		msg1.MetaSetMut("row_type", "data")
+
+		empty := service.NewMessage(nil)
+		empty.SetStructuredMut(map[string]any{"counter_id": 1})
+		empty.MetaSetMut("row_type", "empty")
+
+		msg, err := TableMessage(service.MessageBatch{empty, msg1})
+		assert.NoError(t, err)
+
+		realMsg, err := msg.AsStructured()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"rows": []any{
+				map[string]any{"date": "2023-10-26", "visits": 100.0},
+			},
+		}, realMsg)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		msg, err := TableMessage(nil)
+		assert.NoError(t, err)
+
+		realMsg, err := msg.AsStructured()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"rows": []any{}}, realMsg)
+	})
+}
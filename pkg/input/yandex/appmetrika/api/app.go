@@ -7,6 +7,9 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
+// AppStatusDeleted is the status of a deleted application.
+const AppStatusDeleted = "deleted"
+
 type AppService struct {
 	client *Client
 }
@@ -36,9 +39,10 @@ type AppsResponse struct {
 
 // GoalsResponseEntry represents a single goal entry in a GoalsResponse.
 type AppsResponseEntry struct {
-	Id    uint64 `json:"id"`    // Id is the unique identifier of the goal.
-	Name  string `json:"name"`  // Name is the name of the goal.
-	Label string `json:"label"` // Label is name of the folder where the application is located in the web interface.
+	Id     uint64 `json:"id"`               // Id is the unique identifier of the goal.
+	Name   string `json:"name"`             // Name is the name of the goal.
+	Label  string `json:"label"`            // Label is name of the folder where the application is located in the web interface.
+	Status string `json:"status,omitempty"` // Status is the application status, e.g. active or deleted.
 }
 
 // Batch creates a service.MessageBatch from the GoalsResponse.
@@ -0,0 +1,38 @@
+package stat_table
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika/api"
+)
+
+// resolveIDs fetches the applications list and returns IDs of the applications matching the selector.
+// The application folder is used as the label. Deleted applications are skipped.
+func resolveIDs(ctx context.Context, client *api.Client, s *stattable.Selector) ([]int, error) {
+	data, err := client.App.GetWithContext(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]stattable.Entry, 0, len(data.Data))
+
+	for _, c := range data.Data {
+		entries = append(entries, stattable.Entry{
+			ID:      int(c.Id),
+			Name:    c.Name,
+			Labels:  []string{c.Label},
+			Deleted: strings.EqualFold(c.Status, api.AppStatusDeleted),
+		})
+	}
+
+	ids := s.Select(entries)
+
+	if len(ids) == 0 {
+		return nil, errors.New("no applications match the ids selector")
+	}
+
+	return ids, nil
+}
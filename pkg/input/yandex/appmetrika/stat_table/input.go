@@ -2,17 +2,21 @@ package stat_table
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
-	"github.com/Jeffail/shutdown"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
 	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika/api"
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
 const (
-	apiKind    = "stat"
-	apiVersion = "v1"
-	pageLimit  = 1000
+	apiKind        = "stat"
+	apiVersion     = "v1"
+	mgmtAPIKind    = "management"
+	mgmtAPIVersion = "v1"
+	pageLimit      = 1000
 )
 
 func init() {
@@ -28,22 +32,23 @@ func init() {
 }
 
 type benthosInput struct {
-	token     string
-	done      bool
-	fetched   int
-	total     int
-	query     *api.StatTableQuery
-	client    *api.Client
-	logger    *service.Logger
-	shutSig   *shutdown.Signaller
-	clientMut sync.Mutex
+	token      string
+	opts       stattable.Options
+	query      *api.StatTableQuery
+	ids        *stattable.Selector
+	reader     *stattable.Reader
+	client     *api.Client
+	mgmtClient *api.Client
+	logger     *service.Logger
+	clientMut  sync.Mutex
 }
 
 func (input *benthosInput) Connect(ctx context.Context) error {
 	input.clientMut.Lock()
 	defer input.clientMut.Unlock()
 
-	if input.client != nil {
+	// the reader is set once the report plan is built, a failed connection is retried
+	if input.reader != nil {
 		return nil
 	}
 
@@ -56,57 +61,61 @@ func (input *benthosInput) Connect(ctx context.Context) error {
 
 	input.client = apiClient
 
-	return nil
-}
-
-func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
-	input.clientMut.Lock()
-	defer input.clientMut.Unlock()
-
-	if input.done {
-		return nil, nil, service.ErrEndOfInput
-	}
+	input.mgmtClient = api.NewClient(
+		mgmtAPIKind,
+		mgmtAPIVersion,
+		input.token,
+		input.logger,
+	)
 
-	input.query.Offset = input.fetched + 1
+	if input.ids != nil {
+		ids, err := resolveIDs(ctx, input.mgmtClient, input.ids)
+		if err != nil {
+			return fmt.Errorf("can't resolve applications: %w", err)
+		}
 
-	input.logger.Info("Fetch Yandex.AppMetrika API data")
+		input.query.IDs = ids
 
-	data, err := input.client.StatTable.GetWithContext(ctx, input.query)
-	if err != nil {
-		return nil, nil, service.ErrEndOfInput
+		input.logger.
+			With("ids", ids).
+			Info("applications are resolved")
 	}
 
-	if data == nil {
-		input.logger.
-			Warn("response return no data")
+	reader := stattable.NewReader(input.client.StatTable, input.opts, input.logger)
 
-		return nil, nil, service.ErrEndOfInput
+	if err := reader.Start(ctx, input.query); err != nil {
+		return fmt.Errorf("can't build report plan: %w", err)
 	}
 
-	if data.TotalRows == 0 {
-		input.logger.
-			Warn("response return 0 rows")
+	input.reader = reader
 
-		return nil, nil, service.ErrEndOfInput
-	}
+	return nil
+}
 
-	if input.total == 0 {
-		input.total = data.TotalRows
+func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	input.clientMut.Lock()
+	defer input.clientMut.Unlock()
+
+	if input.reader == nil {
+		return nil, nil, service.ErrNotConnected
 	}
 
-	input.fetched += len(data.Data)
-	input.done = input.total > 0 && input.fetched >= input.total
+	msgs, ack, err := input.reader.ReadBatch(ctx)
+	if errors.Is(err, stattable.ErrReadFailed) {
+		// the failed report is read again by the next connection
+		input.reader.Close()
+		input.reader = nil
 
-	msgs, err := data.Batch()
-	if err != nil {
-		return nil, nil, err
+		return nil, nil, service.ErrNotConnected
 	}
 
-	ack := func(context.Context, error) error { return nil }
-
-	return msgs, ack, nil
+	return msgs, ack, err
 }
 
 func (input *benthosInput) Close(ctx context.Context) error {
+	if input.reader != nil {
+		input.reader.Close()
+	}
+
 	return nil
 }
@@ -1,7 +1,8 @@
 package stat_table
 
 import (
-	"github.com/Jeffail/shutdown"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
 	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
 	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/appmetrika/api"
 	"github.com/redpanda-data/benthos/v4/public/service"
@@ -9,9 +10,8 @@ import (
 
 func inputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
 	input := &benthosInput{
-		logger:  mgr.Logger(),
-		shutSig: shutdown.NewSignaller(),
-		query:   &api.StatTableQuery{},
+		logger: mgr.Logger(),
+		query:  &api.StatTableQuery{},
 	}
 
 	input.query.Offset = 0
@@ -19,7 +19,7 @@ func inputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (servic
 
 	var err error
 
-	input.query.IDs, err = conf.FieldIntList("ids")
+	input.query.IDs, input.ids, err = stattable.IDsFromConfig(conf, "application")
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +91,11 @@ func inputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (servic
 		}
 	}
 
+	input.query.ProposedAccuracy, err = conf.FieldBool("proposed_accuracy")
+	if err != nil {
+		return nil, err
+	}
+
 	if conf.Contains("lang") {
 		input.query.Lang, err = conf.FieldString("lang")
 		if err != nil {
@@ -112,6 +117,123 @@ func inputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (servic
 		}
 	}
 
+	input.opts.SettleDays, err = conf.FieldInt("settle_days")
+	if err != nil {
+		return nil, err
+	}
+
+	if input.opts.SettleDays > 0 {
+		input.query.Date1, err = utils.SettleDate(input.query.Date1, input.opts.SettleDays)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input.opts.Format, err = conf.FieldString("format")
+	if err != nil {
+		return nil, err
+	}
+
+	input.opts.Batch.DimensionIDs, err = conf.FieldString("dimension_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	input.opts.Batch.Shape, err = conf.FieldString("shape")
+	if err != nil {
+		return nil, err
+	}
+
+	input.opts.Batch.Keys.Strategy, err = conf.FieldString("key_strategy")
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains("key_aliases") {
+		input.opts.Batch.Keys.Aliases, err = conf.FieldStringMap("key_aliases")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	typed, err := conf.FieldBool("typed_metrics")
+	if err != nil {
+		return nil, err
+	}
+
+	if typed {
+		input.opts.Batch.Catalog = catalog.AppMetrika
+	}
+
+	input.opts.Totals, err = conf.FieldBool("emit_totals")
+	if err != nil {
+		return nil, err
+	}
+
+	input.opts.FailOnSampled, err = conf.FieldBool("fail_on_sampled")
+	if err != nil {
+		return nil, err
+	}
+
+	input.opts.FanOut, err = conf.FieldBool("fan_out")
+	if err != nil {
+		return nil, err
+	}
+
+	input.opts.Concurrency = 1
+
+	if input.opts.FanOut {
+		input.opts.Concurrency, err = conf.FieldInt("fan_out_concurrency")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("max_metrics") {
+		input.opts.MaxMetrics, err = conf.FieldInt("max_metrics")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("currency") {
+		input.query.Currency, err = conf.FieldString("currency")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input.query.IncludeUndefined, err = conf.FieldBool("include_undefined")
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains("group") {
+		input.query.Group, err = conf.FieldString("group")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("quantile") {
+		input.query.Quantile, err = conf.FieldInt("quantile")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("attribution") {
+		input.query.Attribution, err = conf.FieldString("attribution")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input.query.Pretty, err = conf.FieldBool("pretty")
+	if err != nil {
+		return nil, err
+	}
+
 	if conf.Contains("direct_client_logins") {
 		input.query.DirectLogins, err = conf.FieldStringList("direct_client_logins")
 		if err != nil {
@@ -1,6 +1,10 @@
 package stat_table
 
-import "github.com/redpanda-data/benthos/v4/public/service"
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
 
 func inputConfig() *service.ConfigSpec {
 	return service.NewConfigSpec().
@@ -12,9 +16,12 @@ func inputConfig() *service.ConfigSpec {
 				Description("Yandex.AppMetrika API token").
 				Secret().
 				Optional(),
-			service.NewIntListField("ids").
-				Description("Yandex.AppMetrika Counter IDs").
-				Example([]int{44147844, 2215573}),
+			service.NewAnyField("ids").
+				Description("Yandex.AppMetrika application IDs, `all` to read all applications available to the token or an object with the `name` and `label` regular expressions to select applications by name and folder. Applications are resolved on connect, deleted applications are skipped.").
+				Example([]int{44147844, 2215573}).
+				Example("all").
+				Example(map[string]any{"name": "^Shop"}).
+				LintRule(stattable.IDsLintRule("application")),
 			service.NewStringListField("metrics").
 				Description("A list of metrics.").
 				Example([]string{"ym:s:pageviews", "ym:s:visits", "ym:s:users"}),
@@ -36,8 +43,13 @@ func inputConfig() *service.ConfigSpec {
 				Description("A list of dimensions and metrics to use for sorting.").
 				Optional(),
 			service.NewStringField("accuracy").
-				Description("Sample size for the report.").
-				Optional(),
+				Description("Sample size for the report: `low`, `medium`, `high`, `full` or a number in the (0; 1] range.").
+				Examples("full", "0.1").
+				Optional().
+				LintRule(`root = if !["low", "medium", "high", "full"].contains(this) && (this.number().catch(-1) <= 0 || this.number().catch(-1) > 1) { ["accuracy must be one of low, medium, high, full or a number in the (0; 1] range"] }`),
+			service.NewBoolField("proposed_accuracy").
+				Description("Let the API choose the accuracy to return the report faster.").
+				Default(false),
 			service.NewStringField("lang").
 				Description("Language.").
 				Example("en").
@@ -50,8 +62,89 @@ func inputConfig() *service.ConfigSpec {
 				Description("Time zone in ±hh:mm format within the range of [-23:59; +23:59]").
 				Example("+03:00").
 				Optional(),
+			service.NewBoolField("fan_out").
+				Description("Issue a separate query per application instead of aggregating all applications in one report. Each row gets the `counter_id` field and metadata field.").
+				Default(false),
+			service.NewIntField("fan_out_concurrency").
+				Description("Maximum number of concurrent queries in the fan-out mode.").
+				Default(3).
+				LintRule(`root = if this < 1 { ["field must be greater than 0"] }`),
+			stattable.MaxMetricsField(maxMetrics),
+			service.NewStringField("currency").
+				Description("Currency for money metrics as ISO 4217 code.").
+				Example("RUB").
+				Optional().
+				LintRule(`root = if !this.re_match("^[A-Z]{3}$") { ["currency must be a three-letter ISO 4217 code"] }`),
+			service.NewBoolField("include_undefined").
+				Description("Include rows with undefined dimension values.").
+				Default(false),
+			service.NewStringEnumField("group", "all", "auto", "minute", "dekaminute", "hour", "hours", "day", "week", "month", "quarter", "year").
+				Description("Time grouping for the time dimensions.").
+				Example("day").
+				Optional(),
+			service.NewIntField("quantile").
+				Description("Quantile in percents for the quantile metrics.").
+				Example(90).
+				Optional().
+				LintRule(`root = if this <= 0 || this > 100 { ["quantile must be in the (0; 100] range"] }`),
+			service.NewStringEnumField("attribution", "first", "last", "lastsign", "last_yandex_direct_click", "cross_device_last_significant", "cross_device_first", "cross_device_last_yandex_direct_click", "cross_device_last", "automatic").
+				Description("Attribution model for the `<attribution>` parametrized fields.").
+				Example("lastsign").
+				Optional(),
+			service.NewBoolField("pretty").
+				Description("Request a formatted API response.").
+				Default(false),
 			service.NewStringListField("direct_client_logins").
 				Description("A list of usernames of Yandex Direct clients").
 				Optional(),
-		)
+			service.NewStringEnumField("format", "json", "csv").
+				Description("Response format. The `csv` format streams rows from the `data.csv` endpoint and is cheaper to decode for large reports, but it does not provide the total rows number.").
+				Default("json"),
+			service.NewStringAnnotatedEnumField("dimension_ids", map[string]string{
+				"none":   "Emit dimension names only.",
+				"field":  "Emit dimension IDs as separate `<dimension>_id` fields.",
+				"object": "Emit dimensions as objects with `name` and `id` fields.",
+			}).
+				Description("How to emit dimension IDs. Dimension names are localized and may change over time, IDs are stable. Not supported by the `csv` format.").
+				Default("none"),
+			service.NewStringAnnotatedEnumField("shape", map[string]string{
+				"wide":  "One message per row with dimensions and metrics as fields.",
+				"long":  "One message per row and metric with dimensions and the `metric` and `value` fields.",
+				"table": "The whole report as one message with the `rows` array and the optional `totals` object.",
+			}).
+				Description("Shape of the report messages.").
+				Default("wide"),
+			service.NewStringAnnotatedEnumField("key_strategy", map[string]string{
+				"raw":      "Keep API field names as is, e.g. `ym:s:lastTrafficSource`.",
+				"stripped": "Remove the namespace prefix, e.g. `lastTrafficSource`.",
+				"snake":    "Remove the namespace prefix and convert to snake case, e.g. `last_traffic_source`.",
+				"alias":    "Use `key_aliases` and keep other names as is.",
+			}).
+				Description("How to convert metric and dimension names to message keys.").
+				Default("snake"),
+			service.NewStringMapField("key_aliases").
+				Description("Custom message keys for metrics and dimensions. Aliases take precedence over `key_strategy`.").
+				Example(map[string]string{"ym:s:visits": "sessions"}).
+				Optional(),
+			service.NewBoolField("typed_metrics").
+				Description("Use the metric types of the built-in metrics catalog to emit counter metrics such as visits or users as integers. The `metric_types` metadata field maps metric keys to `integer`, `float`, `percent`, `duration` or `currency` types. Metrics missing from the catalog keep float values and have no type.").
+				Default(false),
+			service.NewBoolField("emit_totals").
+				Description("Emit a separate message with the report totals. The message has the `row_type` metadata field set to `totals`. Not supported by the `csv` format.").
+				Default(false),
+			service.NewBoolField("fail_on_sampled").
+				Description("Fail the report with an error if the report data is sampled. A failed report is read again after the input reconnects. Not supported by the `csv` format.").
+				Default(false),
+			service.NewIntField("settle_days").
+				Description("Re-fetch N days before `date1` as the recent data keeps changing. The report is read per application and day, each row gets the `counter_id` field and the `replace_partition` metadata field with the `counter_id` and `date` keys, so sinks can replace the partition atomically. A partition without rows is emitted as a single empty message with the `row_type` metadata field set to `empty`, so sinks can clear it.").
+				Default(0).
+				LintRule(`root = if this < 0 { ["settle_days must not be negative"] }`),
+			service.NewBoolField("catalog_validation").
+				Description("Reject metrics and dimensions missing from the built-in catalog at lint time. Disable it to query the API fields the catalog does not know yet.").
+				Default(true),
+		).
+		LintRule(stattable.LintRule(catalog.AppMetrika, maxMetrics))
 }
+
+// maxMetrics is the metrics limit of a query.
+const maxMetrics = 20
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+)
+
+// CDP entity types.
+const (
+	CDPEntityOrders   = "simple_orders"
+	CDPEntityContacts = "contacts"
+)
+
+// CDPService uploads CRM data with the CDP API. The client should be created with the cdp/api kind.
+type CDPService struct {
+	client *Client
+}
+
+func (s *CDPService) UploadJSON(counter int, entity string, q *CDPQuery, rows []any) (*CDPUploadResponse, error) {
+	return s.UploadJSONWithContext(context.Background(), counter, entity, q, rows)
+}
+
+// UploadJSONWithContext uploads the entity rows as a JSON document.
+func (s *CDPService) UploadJSONWithContext(ctx context.Context, counter int, entity string, q *CDPQuery, rows []any) (*CDPUploadResponse, error) {
+	var data CDPUploadResponse
+
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "orders"
+	if entity == CDPEntityContacts {
+		key = "contacts"
+	}
+
+	_, err = s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("entity", entity).
+		SetQueryString(values.Encode()).
+		SetBody(map[string]any{key: rows}).
+		SetSuccessResult(&data).
+		Post("counter/{counter_id}/data/{entity}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *CDPService) UploadCSV(counter int, entity string, q *CDPQuery, file []byte) (*CDPUploadResponse, error) {
+	return s.UploadCSVWithContext(context.Background(), counter, entity, q, file)
+}
+
+// UploadCSVWithContext uploads the entity rows as a CSV file.
+func (s *CDPService) UploadCSVWithContext(ctx context.Context, counter int, entity string, q *CDPQuery, file []byte) (*CDPUploadResponse, error) {
+	var data CDPUploadResponse
+
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("entity", entity).
+		SetQueryString(values.Encode()).
+		SetFileBytes("file", entity+".csv", file).
+		SetSuccessResult(&data).
+		Post("counter/{counter_id}/data/{entity}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// CDPQuery represents parameters of the CDP upload.
+type CDPQuery struct {
+	MergeMode     string `json:"merge_mode" url:"merge_mode"`                             // MergeMode is the merge mode of the existing entities: APPEND, SAVE or UPDATE.
+	DelimiterType string `json:"delimiter_type,omitempty" url:"delimiter_type,omitempty"` // DelimiterType is the CSV delimiter type, e.g. COMMA.
+}
+
+// CDPUploadResponse represents a response of the CDP upload.
+type CDPUploadResponse struct {
+	UploadingID string        `json:"uploading_id,omitempty"` // UploadingID is the identifier of the upload.
+	Errors      []CDPRowError `json:"errors,omitempty"`       // Errors is a list of the rejected rows.
+}
+
+// CDPRowError represents an error of a rejected row.
+type CDPRowError struct {
+	Row     int    `json:"row"`     // Row is the zero-based index of the row in the upload.
+	Message string `json:"message"` // Message is a human-readable error message.
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCDPService_UploadJSONWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/counter/1/data/contacts", r.URL.Path)
+		assert.Equal(t, "SAVE", r.URL.Query().Get("merge_mode"))
+
+		var body map[string][]map[string]any
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Len(t, body["contacts"], 2)
+
+		fmt.Fprint(w, `{"uploading_id": "u1", "errors": [{"row": 1, "message": "invalid email"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("cdp/api", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	rows := []any{
+		map[string]any{"uniq_id": "1", "emails": []string{"a@example.com"}},
+		map[string]any{"uniq_id": "2", "emails": []string{"invalid"}},
+	}
+
+	resp, err := client.CDP.UploadJSONWithContext(context.Background(), 1, CDPEntityContacts, &CDPQuery{MergeMode: "SAVE"}, rows)
+	require.NoError(t, err)
+	assert.Equal(t, &CDPUploadResponse{UploadingID: "u1", Errors: []CDPRowError{{Row: 1, Message: "invalid email"}}}, resp)
+}
+
+func TestCDPService_UploadCSVWithContext(t *testing.T) {
+	data := []byte("id,client_uniq_id,create_date_time,order_status,revenue\n1,c1,2024-01-01 00:00:00,PAID,100\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/counter/1/data/simple_orders", r.URL.Path)
+		assert.Equal(t, "APPEND", r.URL.Query().Get("merge_mode"))
+		assert.Equal(t, "COMMA", r.URL.Query().Get("delimiter_type"))
+
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, data, content)
+
+		fmt.Fprint(w, `{"uploading_id": "u2"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("cdp/api", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.CDP.UploadCSVWithContext(context.Background(), 1, CDPEntityOrders, &CDPQuery{MergeMode: "APPEND", DelimiterType: "COMMA"}, data)
+	require.NoError(t, err)
+	assert.Equal(t, &CDPUploadResponse{UploadingID: "u2"}, resp)
+}
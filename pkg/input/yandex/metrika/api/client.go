@@ -18,11 +18,20 @@ const (
 // Client is a wrapper around req.Client for interacting with the Yandex.Metrika API.
 // It provides methods for creating and sending API requests, handling errors, and managing authentication.
 type Client struct {
-	client     *req.Client
-	logger     *service.Logger
-	Goal       *GoalService
-	StatTable  *StatTableService
-	LogRequest *LogRequestService
+	client            *req.Client
+	logger            *service.Logger
+	CDP               *CDPService
+	Goal              *GoalService
+	Grant             *GrantService
+	Counter           *CounterService
+	Expense           *ExpenseService
+	Filter            *FilterService
+	OfflineConversion *OfflineConversionService
+	Operation         *OperationService
+	Segment           *SegmentService
+	StatTable         *StatTableService
+	LogRequest        *LogRequestService
+	UserParam         *UserParamService
 }
 
 // R creates and returns a new req.Request instance.
@@ -92,9 +101,18 @@ func NewClient(kind, version, token string, logger *service.Logger) *Client {
 		logger: logger,
 	}
 
+	c.CDP = &CDPService{client: c}
+	c.Counter = &CounterService{client: c}
+	c.Expense = &ExpenseService{client: c}
+	c.Filter = &FilterService{client: c}
 	c.Goal = &GoalService{client: c}
+	c.Grant = &GrantService{client: c}
 	c.LogRequest = &LogRequestService{client: c}
+	c.OfflineConversion = &OfflineConversionService{client: c}
+	c.Operation = &OperationService{client: c}
+	c.Segment = &SegmentService{client: c}
 	c.StatTable = &StatTableService{client: c}
+	c.UserParam = &UserParamService{client: c}
 
 	return c
 }
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// counterPageLimit is the maximum number of counters per page.
+const counterPageLimit = 1000
+
+// Counter statuses.
+const (
+	CounterStatusActive  = "Active"
+	CounterStatusDeleted = "Deleted"
+)
+
+type CounterService struct {
+	client *Client
+}
+
+func (s *CounterService) Get(q *CountersQuery) (*CountersResponse, error) {
+	return s.GetWithContext(context.Background(), q)
+}
+
+// GetWithContext fetches all counters available to the token page by page.
+// A nil query fetches all counters without filters.
+func (s *CounterService) GetWithContext(ctx context.Context, q *CountersQuery) (*CountersResponse, error) {
+	if q == nil {
+		q = &CountersQuery{}
+	}
+
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
+	}
+
+	all := &CountersResponse{
+		Data: []CountersResponseEntry{},
+	}
+
+	for {
+		var data CountersResponse
+
+		values.Set("per_page", strconv.Itoa(counterPageLimit))
+		values.Set("offset", strconv.Itoa(len(all.Data)+1))
+
+		_, err := s.client.R().
+			SetContext(ctx).
+			SetQueryString(values.Encode()).
+			SetSuccessResult(&data).
+			Get("counters")
+		if err != nil {
+			return nil, err
+		}
+
+		all.Rows = data.Rows
+		all.Data = append(all.Data, data.Data...)
+
+		if len(data.Data) == 0 || len(all.Data) >= data.Rows {
+			return all, nil
+		}
+	}
+}
+
+// CountersQuery represents filters of the counters list request.
+type CountersQuery struct {
+	Permission   string   `json:"permission,omitempty" url:"permission,omitempty"`       // Permission filters counters by the access level: own, view or edit.
+	Status       string   `json:"status,omitempty" url:"status,omitempty"`               // Status filters counters by the status: Active or Deleted.
+	SearchString string   `json:"search_string,omitempty" url:"search_string,omitempty"` // SearchString filters counters by a substring of the ID, name, site or mirrors.
+	LabelID      int      `json:"label_id,omitempty" url:"label_id,omitempty"`           // LabelID filters counters by the label.
+	Field        []string `json:"field,omitempty" url:"field,comma,omitempty"`           // Field is a list of optional counter fields, e.g. goals or mirrors.
+}
+
+// CountersResponse represents a response containing a list of counters from the Yandex.Metrika API.
+type CountersResponse struct {
+	Rows int                     `json:"rows"`     // Rows is the total number of counters.
+	Data []CountersResponseEntry `json:"counters"` // Data is a list of counter entries.
+}
+
+// CountersResponseEntry represents a single counter entry in a CountersResponse.
+type CountersResponseEntry struct {
+	Id           uint64               `json:"id"`                       // Id is the unique identifier of the counter.
+	Name         string               `json:"name"`                     // Name is the name of the counter.
+	Site         string               `json:"site,omitempty"`           // Site is the domain of the counter.
+	Type         string               `json:"type,omitempty"`           // Type is the counter type, e.g. simple.
+	Status       string               `json:"status,omitempty"`         // Status is the counter status, e.g. Active.
+	CodeStatus   string               `json:"code_status,omitempty"`    // CodeStatus is the status of the counter code installation.
+	OwnerLogin   string               `json:"owner_login,omitempty"`    // OwnerLogin is the login of the counter owner.
+	Permission   string               `json:"permission,omitempty"`     // Permission is the access level of the token to the counter.
+	TimeZoneName string               `json:"time_zone_name,omitempty"` // TimeZoneName is the timezone of the counter, e.g. Europe/Moscow.
+	CreateTime   string               `json:"create_time,omitempty"`    // CreateTime is the creation time of the counter.
+	Favorite     int                  `json:"favorite,omitempty"`       // Favorite indicates whether the counter is a favorite.
+	Labels       []CounterLabel       `json:"labels,omitempty"`         // Labels is a list of labels the counter belongs to.
+	Goals        []GoalsResponseEntry `json:"goals,omitempty"`          // Goals is a list of the counter goals, requested with the goals field.
+	Mirrors      []map[string]any     `json:"mirrors2,omitempty"`       // Mirrors is a list of the counter site mirrors, requested with the mirrors2 field.
+	Grants       []map[string]any     `json:"grants,omitempty"`         // Grants is a list of the counter access grants, requested with the grants field.
+	Filters      []map[string]any     `json:"filters,omitempty"`        // Filters is a list of the counter filters, requested with the filters field.
+	Operations   []map[string]any     `json:"operations,omitempty"`     // Operations is a list of the counter operations, requested with the operations field.
+}
+
+// CounterLabel represents a counter label.
+type CounterLabel struct {
+	Id   uint64 `json:"id"`   // Id is the unique identifier of the label.
+	Name string `json:"name"` // Name is the name of the label.
+}
+
+// Batch creates a service.MessageBatch from the CountersResponse.
+func (r *CountersResponse) Batch() (service.MessageBatch, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+
+	msgs := make(service.MessageBatch, len(r.Data))
+
+	for i, row := range r.Data {
+		msg := service.NewMessage(nil)
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+
+		// can't set struct (only slice or map)
+		msg.SetBytes(b)
+
+		msgs[i] = msg
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type FilterService struct {
+	client *Client
+}
+
+func (s *FilterService) Get(counter int) (*FiltersResponse, error) {
+	return s.GetWithContext(context.Background(), counter)
+}
+
+// GetWithContext fetches the filters of the counter.
+func (s *FilterService) GetWithContext(ctx context.Context, counter int) (*FiltersResponse, error) {
+	var data FiltersResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Get("counter/{counter_id}/filters")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *FilterService) Create(counter int, filter *FiltersResponseEntry) (*FilterResponse, error) {
+	return s.CreateWithContext(context.Background(), counter, filter)
+}
+
+// CreateWithContext creates a filter of the counter.
+func (s *FilterService) CreateWithContext(ctx context.Context, counter int, filter *FiltersResponseEntry) (*FilterResponse, error) {
+	var data FilterResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&FilterResponse{Data: *filter}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Post("counter/{counter_id}/filters")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *FilterService) Update(counter int, filter *FiltersResponseEntry) (*FilterResponse, error) {
+	return s.UpdateWithContext(context.Background(), counter, filter)
+}
+
+// UpdateWithContext replaces the counter filter with the ID of the filter.
+func (s *FilterService) UpdateWithContext(ctx context.Context, counter int, filter *FiltersResponseEntry) (*FilterResponse, error) {
+	var data FilterResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&FilterResponse{Data: *filter}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("filter_id", strconv.FormatUint(filter.Id, 10)).
+		Put("counter/{counter_id}/filter/{filter_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *FilterService) Delete(counter int, filter uint64) error {
+	return s.DeleteWithContext(context.Background(), counter, filter)
+}
+
+// DeleteWithContext deletes the counter filter.
+func (s *FilterService) DeleteWithContext(ctx context.Context, counter int, filter uint64) error {
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("filter_id", strconv.FormatUint(filter, 10)).
+		Delete("counter/{counter_id}/filter/{filter_id}")
+
+	return err
+}
+
+// FilterResponse represents a request or a response containing a single filter from the Yandex.Metrika API.
+type FilterResponse struct {
+	Data FiltersResponseEntry `json:"filter"` // Data is the filter entry.
+}
+
+// FiltersResponse represents a response containing a list of counter filters from the Yandex.Metrika API.
+type FiltersResponse struct {
+	Data []FiltersResponseEntry `json:"filters"` // Data is a list of filter entries.
+}
+
+// FiltersResponseEntry represents a single counter traffic filter in a FiltersResponse.
+type FiltersResponseEntry struct {
+	Id             uint64 `json:"id,omitempty"`    // Id is the unique identifier of the filter.
+	Attr           string `json:"attr"`            // Attr is the filtered attribute, e.g. ip, url, title, referer or uniq_id.
+	Type           string `json:"type"`            // Type is the filter condition type, e.g. equal, start, contain, interval or me.
+	Value          string `json:"value"`           // Value is the filter condition value.
+	Action         string `json:"action"`          // Action is the filter action: include or exclude.
+	Status         string `json:"status"`          // Status is the filter status: active or disabled.
+	WithSubdomains bool   `json:"with_subdomains"` // WithSubdomains indicates whether the filter applies to the subdomains.
+}
+
+// Batch creates a service.MessageBatch from the FiltersResponse.
+func (r *FiltersResponse) Batch() (service.MessageBatch, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+
+	msgs := make(service.MessageBatch, len(r.Data))
+
+	for i, row := range r.Data {
+		msg := service.NewMessage(nil)
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+
+		// can't set struct (only slice or map)
+		msg.SetBytes(b)
+
+		msgs[i] = msg
+	}
+
+	return msgs, nil
+}
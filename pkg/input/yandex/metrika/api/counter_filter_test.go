@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterService_GetWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/counter/1/filters", r.URL.Path)
+		fmt.Fprint(w, `{"filters": [{"id": 5, "attr": "ip", "type": "interval", "value": "10.0.0.1-10.0.0.255", "action": "exclude", "status": "active", "with_subdomains": false}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	data, err := client.Filter.GetWithContext(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &FiltersResponse{Data: []FiltersResponseEntry{{Id: 5, Attr: "ip", Type: "interval", Value: "10.0.0.1-10.0.0.255", Action: "exclude", Status: "active"}}}, data)
+}
+
+func TestFilterService_Mutations(t *testing.T) {
+	filter := &FiltersResponseEntry{Id: 5, Attr: "ip", Type: "interval", Value: "10.0.0.1-10.0.0.255", Action: "exclude", Status: "active"}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		call         func(c *Client) (*FilterResponse, error)
+		expectedBody bool
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/counter/1/filters",
+			call: func(c *Client) (*FilterResponse, error) {
+				return c.Filter.CreateWithContext(context.Background(), 1, filter)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/counter/1/filter/5",
+			call: func(c *Client) (*FilterResponse, error) {
+				return c.Filter.UpdateWithContext(context.Background(), 1, filter)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/counter/1/filter/5",
+			call: func(c *Client) (*FilterResponse, error) {
+				return nil, c.Filter.DeleteWithContext(context.Background(), 1, filter.Id)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.method, r.Method)
+				assert.Equal(t, tc.path, r.URL.Path)
+
+				if tc.expectedBody {
+					var body FilterResponse
+
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.Equal(t, *filter, body.Data)
+
+					fmt.Fprint(w, `{"filter": {"id": 5, "attr": "ip", "type": "interval", "value": "10.0.0.1-10.0.0.255", "action": "exclude", "status": "active", "with_subdomains": false}}`)
+
+					return
+				}
+
+				fmt.Fprint(w, `{"success": true}`)
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			resp, err := tc.call(client)
+			assert.NoError(t, err)
+
+			if tc.expectedBody {
+				assert.Equal(t, &FilterResponse{Data: *filter}, resp)
+			}
+		})
+	}
+}
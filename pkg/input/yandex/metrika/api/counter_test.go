@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterService_GetWithContext(t *testing.T) {
+	testCases := []struct {
+		name             string
+		query            *CountersQuery
+		expectedQuery    map[string]string
+		mockResponses    []string
+		mockStatusCode   int
+		expectedCounters *CountersResponse
+		expectedError    error
+	}{
+		{
+			name: "Successful Request",
+			mockResponses: []string{`{
+				"rows": 2,
+				"counters": [
+					{"id": 1, "name": "Shop", "site": "shop.ru", "status": "Active", "labels": [{"id": 10, "name": "prod"}]},
+					{"id": 2, "name": "Blog", "site": "blog.ru", "status": "Active"}
+				]
+			}`},
+			mockStatusCode: http.StatusOK,
+			expectedCounters: &CountersResponse{
+				Rows: 2,
+				Data: []CountersResponseEntry{
+					{Id: 1, Name: "Shop", Site: "shop.ru", Status: "Active", Labels: []CounterLabel{{Id: 10, Name: "prod"}}},
+					{Id: 2, Name: "Blog", Site: "blog.ru", Status: "Active"},
+				},
+			},
+		},
+		{
+			name: "Paginated Request",
+			mockResponses: []string{
+				`{"rows": 2, "counters": [{"id": 1, "name": "Shop"}]}`,
+				`{"rows": 2, "counters": [{"id": 2, "name": "Blog"}]}`,
+			},
+			mockStatusCode: http.StatusOK,
+			expectedCounters: &CountersResponse{
+				Rows: 2,
+				Data: []CountersResponseEntry{
+					{Id: 1, Name: "Shop"},
+					{Id: 2, Name: "Blog"},
+				},
+			},
+		},
+		{
+			name: "Filtered Request",
+			query: &CountersQuery{
+				Permission:   "own",
+				Status:       "Active",
+				SearchString: "shop",
+				LabelID:      10,
+				Field:        []string{"goals", "mirrors2"},
+			},
+			expectedQuery: map[string]string{
+				"permission":    "own",
+				"status":        "Active",
+				"search_string": "shop",
+				"label_id":      "10",
+				"field":         "goals,mirrors2",
+			},
+			mockResponses: []string{`{
+				"rows": 1,
+				"counters": [
+					{"id": 1, "name": "Shop", "time_zone_name": "Europe/Moscow", "permission": "own", "goals": [{"id": 5, "name": "Order", "type": "action"}], "mirrors2": [{"site": "shop.com"}]}
+				]
+			}`},
+			mockStatusCode: http.StatusOK,
+			expectedCounters: &CountersResponse{
+				Rows: 1,
+				Data: []CountersResponseEntry{
+					{
+						Id:           1,
+						Name:         "Shop",
+						TimeZoneName: "Europe/Moscow",
+						Permission:   "own",
+						Goals:        []GoalsResponseEntry{{Id: 5, Name: "Order", Type: "action"}},
+						Mirrors:      []map[string]any{{"site": "shop.com"}},
+					},
+				},
+			},
+		},
+		{
+			name:           "Error Response",
+			mockResponses:  []string{`{"message": "Access denied", "code": 403}`},
+			mockStatusCode: http.StatusForbidden,
+			expectedError: &APIError{
+				Message: "Access denied",
+				Code:    403,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/counters", r.URL.Path)
+				assert.Equal(t, "1000", r.URL.Query().Get("per_page"))
+				assert.Equal(t, fmt.Sprint(page+1), r.URL.Query().Get("offset"))
+
+				for k, v := range tc.expectedQuery {
+					assert.Equal(t, v, r.URL.Query().Get(k))
+				}
+
+				w.WriteHeader(tc.mockStatusCode)
+				fmt.Fprint(w, tc.mockResponses[page])
+
+				page++
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			counters, err := client.Counter.GetWithContext(context.Background(), tc.query)
+
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCounters, counters)
+			}
+		})
+	}
+}
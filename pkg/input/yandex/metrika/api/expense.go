@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+)
+
+type ExpenseService struct {
+	client *Client
+}
+
+func (s *ExpenseService) Upload(counter int, q *ExpenseQuery, data []byte) (*UploadingResponse, error) {
+	return s.UploadWithContext(context.Background(), counter, q, data)
+}
+
+// UploadWithContext uploads a CSV file of advertising expenses to the counter.
+func (s *ExpenseService) UploadWithContext(ctx context.Context, counter int, q *ExpenseQuery, data []byte) (*UploadingResponse, error) {
+	var uploading UploadingResponse
+
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetQueryString(values.Encode()).
+		SetFileBytes("file", "expenses.csv", data).
+		SetSuccessResult(&uploading).
+		Post("counter/{counter_id}/expense/upload")
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploading, nil
+}
+
+func (s *ExpenseService) Delete(counter int, q *ExpenseDeleteQuery) (*UploadingResponse, error) {
+	return s.DeleteWithContext(context.Background(), counter, q)
+}
+
+// DeleteWithContext deletes the counter advertising expenses in the date range.
+func (s *ExpenseService) DeleteWithContext(ctx context.Context, counter int, q *ExpenseDeleteQuery) (*UploadingResponse, error) {
+	var uploading UploadingResponse
+
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetQueryString(values.Encode()).
+		SetSuccessResult(&uploading).
+		Post("counter/{counter_id}/expense/delete")
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploading, nil
+}
+
+func (s *ExpenseService) Get(counter int, uploading uint64) (*UploadingResponse, error) {
+	return s.GetWithContext(context.Background(), counter, uploading)
+}
+
+// GetWithContext fetches the advertising expenses upload info.
+func (s *ExpenseService) GetWithContext(ctx context.Context, counter int, uploading uint64) (*UploadingResponse, error) {
+	var data UploadingResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("uploading_id", strconv.FormatUint(uploading, 10)).
+		SetSuccessResult(&data).
+		Get("counter/{counter_id}/expense/uploading/{uploading_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// ExpenseQuery represents parameters of the advertising expenses upload.
+type ExpenseQuery struct {
+	Provider string `json:"provider,omitempty" url:"provider,omitempty"` // Provider is an optional name of the expenses source, e.g. vk.
+	Comment  string `json:"comment,omitempty" url:"comment,omitempty"`   // Comment is an optional comment of the upload.
+}
+
+// ExpenseDeleteQuery represents parameters of the advertising expenses deletion.
+type ExpenseDeleteQuery struct {
+	Date1     string `json:"date1" url:"date1"`                               // Date1 is the start date of the range in YYYY-MM-DD format.
+	Date2     string `json:"date2" url:"date2"`                               // Date2 is the end date of the range in YYYY-MM-DD format.
+	UTMSource string `json:"utm_source,omitempty" url:"utm_source,omitempty"` // UTMSource limits the deletion to the expenses of the source.
+}
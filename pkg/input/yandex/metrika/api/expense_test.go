@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpenseService_UploadWithContext(t *testing.T) {
+	data := []byte("Date,UTMSource,Expenses,Currency\n2024-01-01,vk,100,RUB\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/counter/1/expense/upload", r.URL.Path)
+		assert.Equal(t, "vk", r.URL.Query().Get("provider"))
+
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, data, content)
+
+		fmt.Fprint(w, `{"uploading": {"id": 4, "source_quantity": 1, "status": "UPLOADED"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.Expense.UploadWithContext(context.Background(), 1, &ExpenseQuery{Provider: "vk"}, data)
+	require.NoError(t, err)
+	assert.Equal(t, &UploadingResponse{Uploading: Uploading{Id: 4, SourceQuantity: 1, Status: "UPLOADED"}}, resp)
+}
+
+func TestExpenseService_DeleteWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/counter/1/expense/delete", r.URL.Path)
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("date1"))
+		assert.Equal(t, "2024-01-31", r.URL.Query().Get("date2"))
+		assert.Equal(t, "vk", r.URL.Query().Get("utm_source"))
+
+		fmt.Fprint(w, `{"uploading": {"id": 5, "status": "UPLOADED"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.Expense.DeleteWithContext(context.Background(), 1, &ExpenseDeleteQuery{Date1: "2024-01-01", Date2: "2024-01-31", UTMSource: "vk"})
+	require.NoError(t, err)
+	assert.Equal(t, &UploadingResponse{Uploading: Uploading{Id: 5, Status: "UPLOADED"}}, resp)
+}
@@ -31,6 +31,97 @@ func (s *GoalService) GetWithContext(ctx context.Context, counter int) (*GoalsRe
 	return &data, nil
 }
 
+func (s *GoalService) Create(counter int, goal *GoalsResponseEntry) (*GoalResponse, error) {
+	return s.CreateWithContext(context.Background(), counter, goal)
+}
+
+// CreateWithContext creates a goal of the counter.
+func (s *GoalService) CreateWithContext(ctx context.Context, counter int, goal *GoalsResponseEntry) (*GoalResponse, error) {
+	var data GoalResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&goalRequest{Data: newGoalBody(*goal)}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Post("counter/{counter_id}/goals")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *GoalService) Update(counter int, goal *GoalsResponseEntry) (*GoalResponse, error) {
+	return s.UpdateWithContext(context.Background(), counter, goal)
+}
+
+// UpdateWithContext replaces the counter goal with the ID of the goal.
+func (s *GoalService) UpdateWithContext(ctx context.Context, counter int, goal *GoalsResponseEntry) (*GoalResponse, error) {
+	var data GoalResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&goalRequest{Data: newGoalBody(*goal)}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("goal_id", strconv.FormatUint(goal.Id, 10)).
+		Put("counter/{counter_id}/goal/{goal_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *GoalService) Delete(counter int, goal uint64) error {
+	return s.DeleteWithContext(context.Background(), counter, goal)
+}
+
+// DeleteWithContext deletes the counter goal.
+func (s *GoalService) DeleteWithContext(ctx context.Context, counter int, goal uint64) error {
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("goal_id", strconv.FormatUint(goal, 10)).
+		Delete("counter/{counter_id}/goal/{goal_id}")
+
+	return err
+}
+
+// GoalResponse represents a request or a response containing a single goal from the Yandex.Metrika API.
+type GoalResponse struct {
+	Data GoalsResponseEntry `json:"goal"` // Data is the goal entry.
+}
+
+// goalRequest represents a request body of the goal mutations.
+type goalRequest struct {
+	Data goalBody `json:"goal"` // Data is the goal entry.
+}
+
+// goalBody is a goal entry of the goal mutations. The goal source is set by Metrika,
+// so an empty source is omitted instead of being sent as an empty string.
+type goalBody struct {
+	GoalsResponseEntry
+
+	Source string     `json:"goal_source,omitempty"` // Source is the source of the goal.
+	Steps  []goalBody `json:"steps,omitempty"`       // Steps is a list of steps associated with the goal.
+}
+
+// newGoalBody converts the goal entry to the mutation body with its steps.
+func newGoalBody(g GoalsResponseEntry) goalBody {
+	body := goalBody{
+		GoalsResponseEntry: g,
+		Source:             g.Source,
+	}
+
+	for _, s := range g.Steps {
+		body.Steps = append(body.Steps, newGoalBody(s))
+	}
+
+	return body
+}
+
 // GoalsResponse represents a response containing a list of goals from the Yandex.Metrika API.
 type GoalsResponse struct {
 	Data []GoalsResponseEntry `json:"goals"` // Data is a list of goal entries.
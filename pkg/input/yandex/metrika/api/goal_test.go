@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -230,3 +231,87 @@ func TestGoalsResponse_Batch(t *testing.T) {
 		})
 	}
 }
+
+func TestGoalService_Mutations(t *testing.T) {
+	goal := &GoalsResponseEntry{
+		Id:   125,
+		Name: "Order",
+		Type: "step",
+		Steps: []GoalsResponseEntry{
+			{Name: "Cart", Type: "url", Conditions: []map[string]string{{"type": "contain", "url": "/cart"}}},
+			{Name: "Checkout", Type: "url", Conditions: []map[string]string{{"type": "contain", "url": "/checkout"}}},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		call         func(c *Client) (*GoalResponse, error)
+		expectedBody bool
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/counter/1/goals",
+			call: func(c *Client) (*GoalResponse, error) {
+				return c.Goal.CreateWithContext(context.Background(), 1, goal)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/counter/1/goal/125",
+			call: func(c *Client) (*GoalResponse, error) {
+				return c.Goal.UpdateWithContext(context.Background(), 1, goal)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/counter/1/goal/125",
+			call: func(c *Client) (*GoalResponse, error) {
+				return nil, c.Goal.DeleteWithContext(context.Background(), 1, goal.Id)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.method, r.Method)
+				assert.Equal(t, tc.path, r.URL.Path)
+
+				if tc.expectedBody {
+					var body GoalResponse
+
+					raw, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					assert.NoError(t, json.Unmarshal(raw, &body))
+					assert.Equal(t, *goal, body.Data)
+					// the empty goal source is set by Metrika
+					assert.NotContains(t, string(raw), "goal_source")
+
+					fmt.Fprint(w, `{"goal": {"id": 125, "name": "Order", "type": "step"}}`)
+
+					return
+				}
+
+				fmt.Fprint(w, `{"success": true}`)
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			resp, err := tc.call(client)
+			assert.NoError(t, err)
+
+			if tc.expectedBody {
+				assert.Equal(t, &GoalResponse{Data: GoalsResponseEntry{Id: 125, Name: "Order", Type: "step"}}, resp)
+			}
+		})
+	}
+}
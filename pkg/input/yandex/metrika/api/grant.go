@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type GrantService struct {
+	client *Client
+}
+
+func (s *GrantService) Get(counter int) (*GrantsResponse, error) {
+	return s.GetWithContext(context.Background(), counter)
+}
+
+// GetWithContext fetches the access grants of the counter.
+func (s *GrantService) GetWithContext(ctx context.Context, counter int) (*GrantsResponse, error) {
+	var data GrantsResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Get("counter/{counter_id}/grants")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// GrantsResponse represents a response containing a list of counter access grants from the Yandex.Metrika API.
+type GrantsResponse struct {
+	Data []GrantsResponseEntry `json:"grants"` // Data is a list of grant entries.
+}
+
+// GrantsResponseEntry represents a single access grant in a GrantsResponse.
+type GrantsResponseEntry struct {
+	UserLogin         string `json:"user_login"`                    // UserLogin is the login of the user the access is granted to.
+	UserUID           uint64 `json:"user_uid,omitempty"`            // UserUID is the UID of the user.
+	Permission        string `json:"perm"`                          // Permission is the access level: public_stat, view or edit.
+	CreatedAt         string `json:"created_at,omitempty"`          // CreatedAt is the creation time of the grant.
+	Comment           string `json:"comment,omitempty"`             // Comment is an optional comment of the grant.
+	PartnerDataAccess bool   `json:"partner_data_access,omitempty"` // PartnerDataAccess indicates whether the user has access to the partner data.
+}
+
+// Batch creates a service.MessageBatch from the GrantsResponse.
+func (r *GrantsResponse) Batch() (service.MessageBatch, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+
+	msgs := make(service.MessageBatch, len(r.Data))
+
+	for i, row := range r.Data {
+		msg := service.NewMessage(nil)
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+
+		// can't set struct (only slice or map)
+		msg.SetBytes(b)
+
+		msgs[i] = msg
+	}
+
+	return msgs, nil
+}
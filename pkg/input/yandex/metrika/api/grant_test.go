@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrantService_GetWithContext(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockResponse   string
+		mockStatusCode int
+		expectedGrants *GrantsResponse
+		expectedError  error
+	}{
+		{
+			name: "Successful Request",
+			mockResponse: `{
+				"grants": [
+					{"user_login": "analyst", "user_uid": 42, "perm": "view", "created_at": "2024-01-01T00:00:00Z", "comment": "BI"},
+					{"user_login": "manager", "perm": "edit", "partner_data_access": true}
+				]
+			}`,
+			mockStatusCode: http.StatusOK,
+			expectedGrants: &GrantsResponse{
+				Data: []GrantsResponseEntry{
+					{UserLogin: "analyst", UserUID: 42, Permission: "view", CreatedAt: "2024-01-01T00:00:00Z", Comment: "BI"},
+					{UserLogin: "manager", Permission: "edit", PartnerDataAccess: true},
+				},
+			},
+		},
+		{
+			name:           "Error Response",
+			mockResponse:   `{"message": "Access denied", "code": 403}`,
+			mockStatusCode: http.StatusForbidden,
+			expectedError: &APIError{
+				Message: "Access denied",
+				Code:    403,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/counter/1/grants", r.URL.Path)
+				w.WriteHeader(tc.mockStatusCode)
+				fmt.Fprint(w, tc.mockResponse)
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			grants, err := client.Grant.GetWithContext(context.Background(), 1)
+
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedGrants, grants)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+)
+
+// Offline conversions upload statuses.
+const (
+	UploadStatusProcessed      = "PROCESSED"
+	UploadStatusLinkageFailure = "LINKAGE_FAILURE"
+)
+
+type OfflineConversionService struct {
+	client *Client
+}
+
+func (s *OfflineConversionService) Upload(counter int, q *OfflineConversionQuery, data []byte) (*UploadingResponse, error) {
+	return s.UploadWithContext(context.Background(), counter, q, data)
+}
+
+// UploadWithContext uploads a CSV file of offline conversions to the counter.
+func (s *OfflineConversionService) UploadWithContext(ctx context.Context, counter int, q *OfflineConversionQuery, data []byte) (*UploadingResponse, error) {
+	var uploading UploadingResponse
+
+	req := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetQueryParam("client_id_type", q.ClientIDType).
+		SetFileBytes("file", "conversions.csv", data).
+		SetSuccessResult(&uploading)
+
+	if q.Comment != "" {
+		req.SetQueryParam("comment", q.Comment)
+	}
+
+	_, err := req.Post("counter/{counter_id}/offline_conversions/upload")
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploading, nil
+}
+
+func (s *OfflineConversionService) Get(counter int, uploading uint64) (*UploadingResponse, error) {
+	return s.GetWithContext(context.Background(), counter, uploading)
+}
+
+// GetWithContext fetches the offline conversions upload info.
+func (s *OfflineConversionService) GetWithContext(ctx context.Context, counter int, uploading uint64) (*UploadingResponse, error) {
+	var data UploadingResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("uploading_id", strconv.FormatUint(uploading, 10)).
+		SetSuccessResult(&data).
+		Get("counter/{counter_id}/offline_conversions/uploading/{uploading_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *OfflineConversionService) UploadCalls(counter int, q *CallsQuery, data []byte) (*UploadingResponse, error) {
+	return s.UploadCallsWithContext(context.Background(), counter, q, data)
+}
+
+// UploadCallsWithContext uploads a CSV file of phone calls to the counter.
+func (s *OfflineConversionService) UploadCallsWithContext(ctx context.Context, counter int, q *CallsQuery, data []byte) (*UploadingResponse, error) {
+	var uploading UploadingResponse
+
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetQueryString(values.Encode()).
+		SetFileBytes("file", "calls.csv", data).
+		SetSuccessResult(&uploading).
+		Post("counter/{counter_id}/offline_conversions/upload_calls")
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploading, nil
+}
+
+func (s *OfflineConversionService) GetCalls(counter int, uploading uint64) (*UploadingResponse, error) {
+	return s.GetCallsWithContext(context.Background(), counter, uploading)
+}
+
+// GetCallsWithContext fetches the phone calls upload info.
+func (s *OfflineConversionService) GetCallsWithContext(ctx context.Context, counter int, uploading uint64) (*UploadingResponse, error) {
+	var data UploadingResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("uploading_id", strconv.FormatUint(uploading, 10)).
+		SetSuccessResult(&data).
+		Get("counter/{counter_id}/offline_conversions/calls_uploading/{uploading_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// OfflineConversionQuery represents parameters of the offline conversions upload.
+type OfflineConversionQuery struct {
+	ClientIDType string `json:"client_id_type"`    // ClientIDType is the type of the visitor identifier: CLIENT_ID, USER_ID or YCLID.
+	Comment      string `json:"comment,omitempty"` // Comment is an optional comment of the upload.
+}
+
+// CallsQuery represents parameters of the phone calls upload.
+type CallsQuery struct {
+	ClientIDType      string `json:"client_id_type" url:"client_id_type"`                               // ClientIDType is the type of the visitor identifier: CLIENT_ID or USER_ID.
+	Comment           string `json:"comment,omitempty" url:"comment,omitempty"`                         // Comment is an optional comment of the upload.
+	NewGoalName       string `json:"new_goal_name,omitempty" url:"new_goal_name,omitempty"`             // NewGoalName is the name of the goal created for the calls.
+	NewCallsThreshold int    `json:"new_calls_threshold,omitempty" url:"new_calls_threshold,omitempty"` // NewCallsThreshold is the number of days a caller is considered new.
+}
+
+// UploadingResponse represents a response containing an upload info from the Yandex.Metrika API.
+type UploadingResponse struct {
+	Uploading Uploading `json:"uploading"` // Uploading is the upload info.
+}
+
+// Uploading represents an upload info.
+type Uploading struct {
+	Id             uint64 `json:"id"`                        // Id is the unique identifier of the upload.
+	SourceQuantity int    `json:"source_quantity,omitempty"` // SourceQuantity is the number of the uploaded rows.
+	LineQuantity   int    `json:"line_quantity,omitempty"`   // LineQuantity is the number of the processed rows.
+	ClientIDType   string `json:"client_id_type,omitempty"`  // ClientIDType is the type of the visitor identifier.
+	Status         string `json:"status"`                    // Status is the upload status, e.g. UPLOADED, PROCESSED or LINKAGE_FAILURE.
+	Comment        string `json:"comment,omitempty"`         // Comment is the comment of the upload.
+	CreateTime     string `json:"create_time,omitempty"`     // CreateTime is the creation time of the upload.
+}
+
+// IsDone reports whether the upload processing is finished.
+func (u *Uploading) IsDone() bool {
+	return u.Status == UploadStatusProcessed || u.Status == UploadStatusLinkageFailure
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineConversionService_UploadWithContext(t *testing.T) {
+	data := []byte("ClientId,Target,DateTime,Price,Currency\n123,order,1700000000,100,RUB\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/counter/1/offline_conversions/upload", r.URL.Path)
+		assert.Equal(t, "CLIENT_ID", r.URL.Query().Get("client_id_type"))
+		assert.Equal(t, "crm", r.URL.Query().Get("comment"))
+
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, data, content)
+
+		fmt.Fprint(w, `{"uploading": {"id": 9, "source_quantity": 1, "client_id_type": "CLIENT_ID", "status": "UPLOADED", "comment": "crm"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.OfflineConversion.UploadWithContext(context.Background(), 1, &OfflineConversionQuery{ClientIDType: "CLIENT_ID", Comment: "crm"}, data)
+	require.NoError(t, err)
+	assert.Equal(t, &UploadingResponse{
+		Uploading: Uploading{Id: 9, SourceQuantity: 1, ClientIDType: "CLIENT_ID", Status: "UPLOADED", Comment: "crm"},
+	}, resp)
+	assert.False(t, resp.Uploading.IsDone())
+}
+
+func TestOfflineConversionService_GetWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/counter/1/offline_conversions/uploading/9", r.URL.Path)
+		fmt.Fprint(w, `{"uploading": {"id": 9, "line_quantity": 1, "status": "PROCESSED"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.OfflineConversion.GetWithContext(context.Background(), 1, 9)
+	require.NoError(t, err)
+	assert.Equal(t, &UploadingResponse{Uploading: Uploading{Id: 9, LineQuantity: 1, Status: "PROCESSED"}}, resp)
+	assert.True(t, resp.Uploading.IsDone())
+}
+
+func TestOfflineConversionService_UploadCallsWithContext(t *testing.T) {
+	data := []byte("ClientId,DateTime,PhoneNumber,TalkDuration,HoldDuration,CallMissed\n123,1700000000,+79990000000,60,5,0\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/counter/1/offline_conversions/upload_calls", r.URL.Path)
+		assert.Equal(t, "CLIENT_ID", r.URL.Query().Get("client_id_type"))
+		assert.Equal(t, "30", r.URL.Query().Get("new_calls_threshold"))
+		assert.False(t, r.URL.Query().Has("comment"))
+
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, data, content)
+
+		fmt.Fprint(w, `{"uploading": {"id": 10, "source_quantity": 1, "status": "UPLOADED"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.OfflineConversion.UploadCallsWithContext(context.Background(), 1, &CallsQuery{ClientIDType: "CLIENT_ID", NewCallsThreshold: 30}, data)
+	require.NoError(t, err)
+	assert.Equal(t, &UploadingResponse{Uploading: Uploading{Id: 10, SourceQuantity: 1, Status: "UPLOADED"}}, resp)
+}
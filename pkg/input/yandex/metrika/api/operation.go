@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type OperationService struct {
+	client *Client
+}
+
+func (s *OperationService) Get(counter int) (*OperationsResponse, error) {
+	return s.GetWithContext(context.Background(), counter)
+}
+
+// GetWithContext fetches the operations of the counter.
+func (s *OperationService) GetWithContext(ctx context.Context, counter int) (*OperationsResponse, error) {
+	var data OperationsResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Get("counter/{counter_id}/operations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *OperationService) Create(counter int, operation *OperationsResponseEntry) (*OperationResponse, error) {
+	return s.CreateWithContext(context.Background(), counter, operation)
+}
+
+// CreateWithContext creates an operation of the counter.
+func (s *OperationService) CreateWithContext(ctx context.Context, counter int, operation *OperationsResponseEntry) (*OperationResponse, error) {
+	var data OperationResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&OperationResponse{Data: *operation}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Post("counter/{counter_id}/operations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *OperationService) Update(counter int, operation *OperationsResponseEntry) (*OperationResponse, error) {
+	return s.UpdateWithContext(context.Background(), counter, operation)
+}
+
+// UpdateWithContext replaces the counter operation with the ID of the operation.
+func (s *OperationService) UpdateWithContext(ctx context.Context, counter int, operation *OperationsResponseEntry) (*OperationResponse, error) {
+	var data OperationResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&OperationResponse{Data: *operation}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("operation_id", strconv.FormatUint(operation.Id, 10)).
+		Put("counter/{counter_id}/operation/{operation_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *OperationService) Delete(counter int, operation uint64) error {
+	return s.DeleteWithContext(context.Background(), counter, operation)
+}
+
+// DeleteWithContext deletes the counter operation.
+func (s *OperationService) DeleteWithContext(ctx context.Context, counter int, operation uint64) error {
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("operation_id", strconv.FormatUint(operation, 10)).
+		Delete("counter/{counter_id}/operation/{operation_id}")
+
+	return err
+}
+
+// OperationResponse represents a request or a response containing a single operation from the Yandex.Metrika API.
+type OperationResponse struct {
+	Data OperationsResponseEntry `json:"operation"` // Data is the operation entry.
+}
+
+// OperationsResponse represents a response containing a list of counter operations from the Yandex.Metrika API.
+type OperationsResponse struct {
+	Data []OperationsResponseEntry `json:"operations"` // Data is a list of operation entries.
+}
+
+// OperationsResponseEntry represents a single counter operation in an OperationsResponse.
+type OperationsResponseEntry struct {
+	Id     uint64 `json:"id,omitempty"` // Id is the unique identifier of the operation.
+	Action string `json:"action"`       // Action is the operation type, e.g. cut_fragment, cut_parameter, merge_https_and_http, to_lower or replace_domain.
+	Attr   string `json:"attr"`         // Attr is the attribute of the operation: url or referer.
+	Value  string `json:"value"`        // Value is the operation value, e.g. a parameter name or a domain.
+	Status string `json:"status"`       // Status is the operation status: active or disabled.
+}
+
+// Batch creates a service.MessageBatch from the OperationsResponse.
+func (r *OperationsResponse) Batch() (service.MessageBatch, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+
+	msgs := make(service.MessageBatch, len(r.Data))
+
+	for i, row := range r.Data {
+		msg := service.NewMessage(nil)
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+
+		// can't set struct (only slice or map)
+		msg.SetBytes(b)
+
+		msgs[i] = msg
+	}
+
+	return msgs, nil
+}
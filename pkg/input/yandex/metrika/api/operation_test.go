@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationService_GetWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/counter/1/operations", r.URL.Path)
+		fmt.Fprint(w, `{"operations": [{"id": 5, "action": "cut_parameter", "attr": "url", "value": "utm_source", "status": "active"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	data, err := client.Operation.GetWithContext(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &OperationsResponse{Data: []OperationsResponseEntry{{Id: 5, Action: "cut_parameter", Attr: "url", Value: "utm_source", Status: "active"}}}, data)
+}
+
+func TestOperationService_Mutations(t *testing.T) {
+	operation := &OperationsResponseEntry{Id: 5, Action: "cut_parameter", Attr: "url", Value: "utm_source", Status: "active"}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		call         func(c *Client) (*OperationResponse, error)
+		expectedBody bool
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/counter/1/operations",
+			call: func(c *Client) (*OperationResponse, error) {
+				return c.Operation.CreateWithContext(context.Background(), 1, operation)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/counter/1/operation/5",
+			call: func(c *Client) (*OperationResponse, error) {
+				return c.Operation.UpdateWithContext(context.Background(), 1, operation)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/counter/1/operation/5",
+			call: func(c *Client) (*OperationResponse, error) {
+				return nil, c.Operation.DeleteWithContext(context.Background(), 1, operation.Id)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.method, r.Method)
+				assert.Equal(t, tc.path, r.URL.Path)
+
+				if tc.expectedBody {
+					var body OperationResponse
+
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.Equal(t, *operation, body.Data)
+
+					fmt.Fprint(w, `{"operation": {"id": 5, "action": "cut_parameter", "attr": "url", "value": "utm_source", "status": "active"}}`)
+
+					return
+				}
+
+				fmt.Fprint(w, `{"success": true}`)
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			resp, err := tc.call(client)
+			assert.NoError(t, err)
+
+			if tc.expectedBody {
+				assert.Equal(t, &OperationResponse{Data: *operation}, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type SegmentService struct {
+	client *Client
+}
+
+func (s *SegmentService) Get(counter int) (*SegmentsResponse, error) {
+	return s.GetWithContext(context.Background(), counter)
+}
+
+// GetWithContext fetches the saved segments of the counter.
+func (s *SegmentService) GetWithContext(ctx context.Context, counter int) (*SegmentsResponse, error) {
+	var data SegmentsResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Get("counter/{counter_id}/apisegment/segments")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *SegmentService) Create(counter int, segment *SegmentsResponseEntry) (*SegmentResponse, error) {
+	return s.CreateWithContext(context.Background(), counter, segment)
+}
+
+// CreateWithContext creates a segment of the counter.
+func (s *SegmentService) CreateWithContext(ctx context.Context, counter int, segment *SegmentsResponseEntry) (*SegmentResponse, error) {
+	var data SegmentResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&SegmentResponse{Data: *segment}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		Post("counter/{counter_id}/apisegment/segments")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *SegmentService) Update(counter int, segment *SegmentsResponseEntry) (*SegmentResponse, error) {
+	return s.UpdateWithContext(context.Background(), counter, segment)
+}
+
+// UpdateWithContext replaces the counter segment with the ID of the segment.
+func (s *SegmentService) UpdateWithContext(ctx context.Context, counter int, segment *SegmentsResponseEntry) (*SegmentResponse, error) {
+	var data SegmentResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&SegmentResponse{Data: *segment}).
+		SetSuccessResult(&data).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("segment_id", strconv.FormatUint(segment.Id, 10)).
+		Put("counter/{counter_id}/apisegment/segment/{segment_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *SegmentService) Delete(counter int, segment uint64) error {
+	return s.DeleteWithContext(context.Background(), counter, segment)
+}
+
+// DeleteWithContext deletes the counter segment.
+func (s *SegmentService) DeleteWithContext(ctx context.Context, counter int, segment uint64) error {
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("segment_id", strconv.FormatUint(segment, 10)).
+		Delete("counter/{counter_id}/apisegment/segment/{segment_id}")
+
+	return err
+}
+
+// SegmentResponse represents a request or a response containing a single segment from the Yandex.Metrika API.
+type SegmentResponse struct {
+	Data SegmentsResponseEntry `json:"segment"` // Data is the segment entry.
+}
+
+// SegmentsResponse represents a response containing a list of segments from the Yandex.Metrika API.
+type SegmentsResponse struct {
+	Data []SegmentsResponseEntry `json:"segments"` // Data is a list of segment entries.
+}
+
+// SegmentsResponseEntry represents a single segment entry in a SegmentsResponse.
+type SegmentsResponseEntry struct {
+	Id          uint64 `json:"segment_id,omitempty"`     // Id is the unique identifier of the segment.
+	CounterID   uint64 `json:"counter_id,omitempty"`     // CounterID is the ID of the segment counter.
+	Name        string `json:"name"`                     // Name is the name of the segment.
+	Expression  string `json:"expression"`               // Expression is the segment filter expression.
+	IsRetarget  bool   `json:"is_retargeting"`           // IsRetarget indicates whether the segment is available for retargeting.
+	Source      string `json:"segment_source,omitempty"` // Source is the source of the segment, e.g. api or interface.
+	CreateTime  string `json:"create_time,omitempty"`    // CreateTime is the creation time of the segment.
+	Description string `json:"description,omitempty"`    // Description is an optional description of the segment.
+}
+
+// Batch creates a service.MessageBatch from the SegmentsResponse.
+func (r *SegmentsResponse) Batch() (service.MessageBatch, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+
+	msgs := make(service.MessageBatch, len(r.Data))
+
+	for i, row := range r.Data {
+		msg := service.NewMessage(nil)
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+
+		// can't set struct (only slice or map)
+		msg.SetBytes(b)
+
+		msgs[i] = msg
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentService_GetWithContext(t *testing.T) {
+	testCases := []struct {
+		name             string
+		mockResponse     string
+		mockStatusCode   int
+		expectedSegments *SegmentsResponse
+		expectedError    error
+	}{
+		{
+			name: "Successful Request",
+			mockResponse: `{
+				"segments": [
+					{
+						"segment_id": 7,
+						"counter_id": 1,
+						"name": "Mobile",
+						"expression": "ym:s:isMobile=='Yes'",
+						"is_retargeting": false,
+						"segment_source": "api",
+						"create_time": "2024-01-01T00:00:00Z"
+					}
+				]
+			}`,
+			mockStatusCode: http.StatusOK,
+			expectedSegments: &SegmentsResponse{
+				Data: []SegmentsResponseEntry{
+					{
+						Id:         7,
+						CounterID:  1,
+						Name:       "Mobile",
+						Expression: "ym:s:isMobile=='Yes'",
+						Source:     "api",
+						CreateTime: "2024-01-01T00:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name:           "Error Response",
+			mockResponse:   `{"message": "Access denied", "code": 403}`,
+			mockStatusCode: http.StatusForbidden,
+			expectedError: &APIError{
+				Message: "Access denied",
+				Code:    403,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/counter/1/apisegment/segments", r.URL.Path)
+				w.WriteHeader(tc.mockStatusCode)
+				fmt.Fprint(w, tc.mockResponse)
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			segments, err := client.Segment.GetWithContext(context.Background(), 1)
+
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSegments, segments)
+			}
+		})
+	}
+}
+
+func TestSegmentService_Mutations(t *testing.T) {
+	segment := &SegmentsResponseEntry{
+		Id:         7,
+		Name:       "Mobile",
+		Expression: "ym:s:isMobile=='Yes'",
+	}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		call         func(c *Client) (*SegmentResponse, error)
+		expectedBody bool
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/counter/1/apisegment/segments",
+			call: func(c *Client) (*SegmentResponse, error) {
+				return c.Segment.CreateWithContext(context.Background(), 1, segment)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/counter/1/apisegment/segment/7",
+			call: func(c *Client) (*SegmentResponse, error) {
+				return c.Segment.UpdateWithContext(context.Background(), 1, segment)
+			},
+			expectedBody: true,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/counter/1/apisegment/segment/7",
+			call: func(c *Client) (*SegmentResponse, error) {
+				return nil, c.Segment.DeleteWithContext(context.Background(), 1, segment.Id)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.method, r.Method)
+				assert.Equal(t, tc.path, r.URL.Path)
+
+				if tc.expectedBody {
+					var body SegmentResponse
+
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.Equal(t, *segment, body.Data)
+
+					fmt.Fprint(w, `{"segment": {"segment_id": 7, "name": "Mobile", "expression": "ym:s:isMobile=='Yes'"}}`)
+
+					return
+				}
+
+				fmt.Fprint(w, `{"success": true}`)
+			}))
+			defer server.Close()
+
+			client := NewClient("management", "v1", "test_token", nil)
+			client.client.SetBaseURL(server.URL)
+
+			resp, err := tc.call(client)
+			assert.NoError(t, err)
+
+			if tc.expectedBody {
+				assert.Equal(t, &SegmentResponse{Data: *segment}, resp)
+			}
+		})
+	}
+}
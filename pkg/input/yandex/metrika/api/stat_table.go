@@ -3,9 +3,8 @@ package api
 import (
 	"context"
 
-	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
 	"github.com/google/go-querystring/query"
-	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
 type StatTableService struct {
@@ -39,77 +38,38 @@ func (s *StatTableService) GetWithContext(ctx context.Context, q *StatTableQuery
 	return &data, nil
 }
 
-// StatTableQuery represents a query for fetching data from Yandex.Metrika API stat tables.
-type StatTableQuery struct {
-	IDs          []int    `json:"ids" url:"ids,comma"`                                                       // IDs is a list of counter IDs.
-	Date1        string   `json:"date1,omitempty" url:"date1,omitempty"`                                     // Date1 is the start date of the data range.
-	Date2        string   `json:"date2,omitempty" url:"date2,omitempty"`                                     // Date2 is the end date of the data range.
-	Dimensions   []string `json:"dimensions,omitempty" url:"dimensions,comma,omitempty"`                     // Dimensions is a list of dimensions for data breakdown.
-	Metrics      []string `json:"metrics,omitempty" url:"metrics,comma,omitempty"`                           // Metrics is a list of metrics to be fetched.
-	Sort         []string `json:"sort,omitempty" url:"sort,comma,omitempty"`                                 // Sort is a list of fields to sort the data by.
-	Accuracy     string   `json:"accuracy,omitempty" url:"accuracy,omitempty"`                               // Accuracy is a sample size for the report.
-	Limit        int      `json:"limit,omitempty" url:"limit,omitempty"`                                     // Limit is the maximum number of rows to return.
-	Offset       int      `json:"offset,omitempty" url:"offset,omitempty"`                                   // Offset is the offset of the first row to return.
-	Filters      string   `json:"filters,omitempty" url:"filters,omitempty"`                                 // Filters is a filter string to apply to the data.
-	Lang         string   `json:"lang,omitempty" url:"lang,omitempty"`                                       // Lang is the language for the response.
-	Preset       string   `json:"preset,omitempty" url:"preset,omitempty"`                                   // Preset is the preset used for the query.
-	Timezone     string   `json:"timezone,omitempty" url:"timezone,omitempty"`                               // Timezone is the timezone to use for the data.
-	DirectLogins []string `json:"direct_client_logins,omitempty" url:"direct_client_logins,comma,omitempty"` // DirectLogins is a list of direct client logins.
-}
-
-// StatTableResponse represents the response from a stat table query.
-type StatTableResponse struct {
-	Query     *StatTableQuery          `json:"query"`      // Query contains the query parameters used to fetch this data.
-	Data      []StatTableResponseEntry `json:"data"`       // Data contains the actual data returned by the query.
-	TotalRows int                      `json:"total_rows"` // TotalRows is the total number of rows matching the query.
-}
-
-// StatTableResponseEntry represents a single row in the stat table data.
-type StatTableResponseEntry struct {
-	Dimensions []struct {
-		Name string `json:"name"`         // Name is the name of the dimension.
-		Id   string `json:"id,omitempty"` // Id is the optional ID of the dimension.
-	} `json:"dimensions"` // Dimensions is a list of dimensions for this row.
-	Metrics []float64 `json:"metrics"` // Metrics is a list of metrics for this row.
+func (s *StatTableService) GetCSV(q *StatTableQuery) (*StatTableCSVResponse, error) {
+	return s.GetCSVWithContext(context.Background(), q)
 }
 
-// Batch creates a service.MessageBatch from the StatTableResponse.
-func (r *StatTableResponse) Batch() (service.MessageBatch, error) {
-	if r.Data == nil {
-		return nil, nil
+// GetCSVWithContext requests the report in the CSV format.
+// The response body is not read into memory: it is streamed by StatTableCSVResponse.Batch.
+func (s *StatTableService) GetCSVWithContext(ctx context.Context, q *StatTableQuery) (*StatTableCSVResponse, error) {
+	values, err := query.Values(q)
+	if err != nil {
+		return nil, err
 	}
 
-	msgs := make(service.MessageBatch, len(r.Data))
-
-	for i, e := range r.Data {
-		row := make(map[string]any)
-
-		for di, d := range e.Dimensions {
-			k := r.Query.Dimensions[di]
-			k = utils.ProcessKey(k)
-			row[k] = d.Name
-		}
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetQueryString(values.Encode()).
+		DisableAutoReadResponse().
+		Get("data.csv")
+	if err != nil {
+		return nil, err
+	}
 
-		for mi, m := range e.Metrics {
-			k := r.Query.Metrics[mi]
-			k = utils.ProcessKey(k)
-			row[k] = m
-		}
+	return &StatTableCSVResponse{Query: q, Body: resp.Body}, nil
+}
 
-		query, err := utils.StructToMap(r.Query)
-		if err != nil {
-			return nil, err
-		}
+// StatTableQuery represents a query for fetching data from Yandex.Metrika API stat tables.
+type StatTableQuery = stattable.Query
 
-		msg := service.NewMessage(nil)
-		msg.SetStructuredMut(row)
-		msg.MetaSetMut("query", query)
-		msg.MetaSetMut("limit", r.Query.Limit)
-		msg.MetaSetMut("offset", r.Query.Offset)
-		msg.MetaSetMut("total", r.TotalRows)
+// StatTableResponse represents the response from a stat table query.
+type StatTableResponse = stattable.Response
 
-		msgs[i] = msg
-	}
+// StatTableResponseEntry represents a single row in the stat table data.
+type StatTableResponseEntry = stattable.ResponseEntry
 
-	return msgs, nil
-}
+// StatTableCSVResponse represents the response from a stat table query in the CSV format.
+type StatTableCSVResponse = stattable.CSVResponse
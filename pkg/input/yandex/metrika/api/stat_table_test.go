@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	"github.com/google/go-querystring/query"
-	"github.com/redpanda-data/benthos/v4/public/service"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,7 +52,7 @@ func TestStatTableService_GetWithContext(t *testing.T) {
 						}{
 							{Name: "2023-10-26"},
 						},
-						Metrics: []float64{100},
+						Metrics: []*float64{ptr(100.0)},
 					},
 				},
 				TotalRows: 1,
@@ -161,145 +160,73 @@ func TestStatTableService_GetWithContext(t *testing.T) {
 	}
 }
 
-func TestStatTableResponse_Batch(t *testing.T) {
-	testCases := []struct {
-		name           string
-		statResponse   StatTableResponse
-		expectedBatch  service.MessageBatch
-		expectedError  error
-		expectedLength int
-	}{
-		{
-			name: "Successful batch creation",
-			statResponse: StatTableResponse{
-				Query: &StatTableQuery{
-					IDs:        []int{123},
-					Metrics:    []string{"ym:s:visits", "ym:s:pageviews"},
-					Dimensions: []string{"ym:s:date"},
-					Date1:      "2023-10-26",
-					Date2:      "2023-10-27",
-					Limit:      10,
-					Offset:     0,
-				},
-				Data: []StatTableResponseEntry{
-					{
-						Dimensions: []struct {
-							Name string `json:"name"`
-							Id   string `json:"id,omitempty"`
-						}{
-							{Name: "2023-10-26"},
-						},
-						Metrics: []float64{100, 200},
-					},
-					{
-						Dimensions: []struct {
-							Name string `json:"name"`
-							Id   string `json:"id,omitempty"`
-						}{
-							{Name: "2023-10-27"},
-						},
-						Metrics: []float64{150, 250},
-					},
-				},
-				TotalRows: 2,
-			},
-			expectedBatch: func() service.MessageBatch {
-				msg1 := service.NewMessage(nil)
-				msg1.SetStructuredMut(
-					map[string]any{
-						"date":      "2023-10-26",
-						"visits":    100.0,
-						"pageviews": 200.0,
-					})
-				msg1.MetaSetMut("query", map[string]any{
-					"ids":        []int{123},
-					"metrics":    []string{"ym:s:visits", "ym:s:pageviews"},
-					"dimensions": []string{"ym:s:date"},
-					"limit":      10,
-					"date1":      "2023-10-26",
-					"date2":      "2023-10-27",
-				})
-				msg1.MetaSetMut("limit", 10)
-				msg1.MetaSetMut("offset", 0)
-				msg1.MetaSetMut("total", 2)
+func TestStatTableService_GetCSVWithContext(t *testing.T) {
+	q := &StatTableQuery{
+		IDs:        []int{123},
+		Metrics:    []string{"ym:s:visits", "ym:s:pageviews"},
+		Dimensions: []string{"ym:s:date"},
+		Limit:      10,
+		Offset:     1,
+	}
 
-				msg2 := service.NewMessage(nil)
-				msg2.SetStructuredMut(map[string]any{
-					"date":      "2023-10-27",
-					"visits":    150.0,
-					"pageviews": 250.0,
-				})
-				msg2.MetaSetMut("query", map[string]any{
-					"ids":        []int{123},
-					"metrics":    []string{"ym:s:visits", "ym:s:pageviews"},
-					"dimensions": []string{"ym:s:date"},
-					"limit":      10,
-					"date1":      "2023-10-26",
-					"date2":      "2023-10-27",
-				})
-				msg2.MetaSetMut("limit", 10)
-				msg2.MetaSetMut("offset", 0)
-				msg2.MetaSetMut("total", 2)
+	t.Run("Successful Request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/data.csv", r.URL.Path)
+			assert.Equal(t, http.MethodGet, r.Method)
 
-				return service.MessageBatch{msg1, msg2}
-			}(),
-			expectedError:  nil,
-			expectedLength: 2,
-		},
-		{
-			name: "Empty Data",
-			statResponse: StatTableResponse{
-				Query: &StatTableQuery{
-					IDs:        []int{123},
-					Metrics:    []string{"ym:s:visits"},
-					Dimensions: []string{"ym:s:date"},
-				},
-				Data:      []StatTableResponseEntry{},
-				TotalRows: 0,
-			},
-			expectedBatch:  nil,
-			expectedError:  nil,
-			expectedLength: 0,
-		},
-		{
-			name: "Nil Data",
-			statResponse: StatTableResponse{
-				Query: &StatTableQuery{
-					IDs:        []int{123},
-					Metrics:    []string{"ym:s:visits"},
-					Dimensions: []string{"ym:s:date"},
-				},
-				TotalRows: 0,
-			},
-			expectedBatch:  nil,
-			expectedError:  nil,
-			expectedLength: 0,
-		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			batch, err := tc.statResponse.Batch()
+			exptectedQuery, _ := query.Values(q)
+			assert.Equal(t, exptectedQuery.Encode(), r.URL.RawQuery)
 
-			if tc.expectedError != nil {
-				assert.Error(t, err)
-				assert.Equal(t, tc.expectedError.Error(), err.Error())
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tc.expectedLength, len(batch))
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, "\"Date of visit\",\"Sessions\",\"Pageviews\"\n\"Total and averages\",250,450\n2023-10-26,100,200\n2023-10-27,150,\n")
+		}))
+		defer server.Close()
 
-				for i := range tc.expectedBatch {
-					expectedMsg, _ := tc.expectedBatch[i].AsStructured()
-					realMsg, _ := batch[i].AsStructured()
-					assert.Equal(t, expectedMsg, realMsg)
+		client := NewClient("stat", "v1", "test_token", nil)
+		client.client.SetBaseURL(server.URL)
 
-					meta := []string{"query", "limit", "offset", "total"}
-					for _, m := range meta {
-						expectedMeta, _ := tc.expectedBatch[i].MetaGetMut(m)
-						realMeta, _ := batch[i].MetaGetMut(m)
-						assert.Equal(t, expectedMeta, realMeta)
-					}
-				}
-			}
-		})
-	}
+		data, err := client.StatTable.GetCSVWithContext(context.Background(), q)
+		assert.NoError(t, err)
+
+		batch, err := data.Batch()
+		assert.NoError(t, err)
+		assert.Len(t, batch, 2)
+
+		expected := []map[string]any{
+			{"date": "2023-10-26", "visits": 100.0, "pageviews": 200.0},
+			{"date": "2023-10-27", "visits": 150.0, "pageviews": nil},
+		}
+
+		for i, msg := range batch {
+			realMsg, _ := msg.AsStructured()
+			assert.Equal(t, expected[i], realMsg)
+
+			limit, _ := msg.MetaGetMut("limit")
+			assert.Equal(t, 10, limit)
+
+			offset, _ := msg.MetaGetMut("offset")
+			assert.Equal(t, 1, offset)
+		}
+	})
+
+	t.Run("Error Response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message": "Something went wrong", "code": 1}`)
+		}))
+		defer server.Close()
+
+		client := NewClient("stat", "v1", "test_token", nil)
+		client.client.SetBaseURL(server.URL)
+
+		var apiErr *APIError
+
+		_, err := client.StatTable.GetCSVWithContext(context.Background(), q)
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, &APIError{Message: "Something went wrong", Code: 1}, apiErr)
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
 }
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"strconv"
+)
+
+type UserParamService struct {
+	client *Client
+}
+
+func (s *UserParamService) Upload(counter int, q *UserParamsQuery, data []byte) (*UserParamsUploadingResponse, error) {
+	return s.UploadWithContext(context.Background(), counter, q, data)
+}
+
+// UploadWithContext uploads a CSV file of user parameters to the counter.
+// The uploading should be confirmed to be processed.
+func (s *UserParamService) UploadWithContext(ctx context.Context, counter int, q *UserParamsQuery, data []byte) (*UserParamsUploadingResponse, error) {
+	var uploading UserParamsUploadingResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetQueryParam("action", q.Action).
+		SetFileBytes("file", "user_params.csv", data).
+		SetSuccessResult(&uploading).
+		Post("counter/{counter_id}/user_params/uploadings/upload")
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploading, nil
+}
+
+func (s *UserParamService) Confirm(counter int, uploading uint64, q *UserParamsQuery) (*UserParamsUploadingResponse, error) {
+	return s.ConfirmWithContext(context.Background(), counter, uploading, q)
+}
+
+// ConfirmWithContext confirms the user parameters uploading.
+func (s *UserParamService) ConfirmWithContext(ctx context.Context, counter int, uploading uint64, q *UserParamsQuery) (*UserParamsUploadingResponse, error) {
+	var data UserParamsUploadingResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("uploading_id", strconv.FormatUint(uploading, 10)).
+		SetBody(map[string]any{"uploading": q}).
+		SetSuccessResult(&data).
+		Post("counter/{counter_id}/user_params/uploading/{uploading_id}/confirm")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (s *UserParamService) Get(counter int, uploading uint64) (*UserParamsUploadingResponse, error) {
+	return s.GetWithContext(context.Background(), counter, uploading)
+}
+
+// GetWithContext fetches the user parameters uploading info.
+func (s *UserParamService) GetWithContext(ctx context.Context, counter int, uploading uint64) (*UserParamsUploadingResponse, error) {
+	var data UserParamsUploadingResponse
+
+	_, err := s.client.R().
+		SetContext(ctx).
+		SetPathParam("counter_id", strconv.Itoa(counter)).
+		SetPathParam("uploading_id", strconv.FormatUint(uploading, 10)).
+		SetSuccessResult(&data).
+		Get("counter/{counter_id}/user_params/uploading/{uploading_id}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// UserParamsQuery represents parameters of the user parameters uploading.
+type UserParamsQuery struct {
+	ContentIDType string `json:"content_id_type"`   // ContentIDType is the type of the visitor identifier: client_id or user_id.
+	Action        string `json:"action"`            // Action is the uploading action: update or delete_keys.
+	Comment       string `json:"comment,omitempty"` // Comment is an optional comment of the uploading.
+}
+
+// UserParamsUploadingResponse represents a response containing a user parameters uploading info from the Yandex.Metrika API.
+type UserParamsUploadingResponse struct {
+	Uploading UserParamsUploading `json:"uploading"` // Uploading is the uploading info.
+}
+
+// UserParamsUploading represents a user parameters uploading info.
+type UserParamsUploading struct {
+	Id            uint64 `json:"id"`                        // Id is the unique identifier of the uploading.
+	ContentIDType string `json:"content_id_type,omitempty"` // ContentIDType is the type of the visitor identifier.
+	Action        string `json:"action,omitempty"`          // Action is the uploading action.
+	Status        string `json:"status"`                    // Status is the uploading status, e.g. need_confirm or is_processed.
+	Comment       string `json:"comment,omitempty"`         // Comment is the comment of the uploading.
+	CreateTime    string `json:"create_time,omitempty"`     // CreateTime is the creation time of the uploading.
+	LineQuantity  int    `json:"line_quantity,omitempty"`   // LineQuantity is the number of the uploaded rows.
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserParamService_UploadAndConfirm(t *testing.T) {
+	data := []byte("client_id,key,value\n123,tier,gold\n")
+	q := &UserParamsQuery{ContentIDType: "client_id", Action: "update", Comment: "crm"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		switch r.URL.Path {
+		case "/counter/1/user_params/uploadings/upload":
+			assert.Equal(t, "update", r.URL.Query().Get("action"))
+
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			assert.Equal(t, data, content)
+
+			fmt.Fprint(w, `{"uploading": {"id": 3, "action": "update", "status": "need_confirm"}}`)
+		case "/counter/1/user_params/uploading/3/confirm":
+			var body struct {
+				Uploading UserParamsQuery `json:"uploading"`
+			}
+
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, *q, body.Uploading)
+
+			fmt.Fprint(w, `{"uploading": {"id": 3, "content_id_type": "client_id", "action": "update", "status": "is_processed", "comment": "crm"}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.UserParam.UploadWithContext(context.Background(), 1, q, data)
+	require.NoError(t, err)
+	assert.Equal(t, &UserParamsUploadingResponse{Uploading: UserParamsUploading{Id: 3, Action: "update", Status: "need_confirm"}}, resp)
+
+	resp, err = client.UserParam.ConfirmWithContext(context.Background(), 1, resp.Uploading.Id, q)
+	require.NoError(t, err)
+	assert.Equal(t, &UserParamsUploadingResponse{
+		Uploading: UserParamsUploading{Id: 3, ContentIDType: "client_id", Action: "update", Status: "is_processed", Comment: "crm"},
+	}, resp)
+}
+
+func TestUserParamService_GetWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/counter/1/user_params/uploading/3", r.URL.Path)
+		fmt.Fprint(w, `{"uploading": {"id": 3, "status": "is_processed", "line_quantity": 10}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("management", "v1", "test_token", nil)
+	client.client.SetBaseURL(server.URL)
+
+	resp, err := client.UserParam.GetWithContext(context.Background(), 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, &UserParamsUploadingResponse{Uploading: UserParamsUploading{Id: 3, Status: "is_processed", LineQuantity: 10}}, resp)
+}
@@ -0,0 +1,126 @@
+package counter_rules
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+// Kinds of the counter rules.
+const (
+	kindFilters    = "filters"
+	kindOperations = "operations"
+)
+
+func init() {
+	err := service.RegisterBatchInput(
+		"yandex_metrika_counter_rules",
+		inputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return inputFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type benthosInput struct {
+	token     string
+	counter   int
+	kinds     []string
+	done      bool
+	client    *api.Client
+	logger    *service.Logger
+	shutSig   *shutdown.Signaller
+	clientMut sync.Mutex
+}
+
+func (input *benthosInput) Connect(ctx context.Context) error {
+	input.clientMut.Lock()
+	defer input.clientMut.Unlock()
+
+	if input.client != nil {
+		return nil
+	}
+
+	apiClient := api.NewClient(
+		apiKind,
+		apiVersion,
+		input.token,
+		input.logger,
+	)
+
+	input.client = apiClient
+
+	return nil
+}
+
+func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	input.clientMut.Lock()
+	defer input.clientMut.Unlock()
+
+	if input.done {
+		return nil, nil, service.ErrEndOfInput
+	}
+
+	input.logger.Info("Fetch Yandex.Metrika API data")
+
+	var msgs service.MessageBatch
+
+	if slices.Contains(input.kinds, kindFilters) {
+		data, err := input.client.Filter.GetWithContext(ctx, input.counter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batch, err := data.Batch()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		msgs = append(msgs, input.setMeta(batch, "filter")...)
+	}
+
+	if slices.Contains(input.kinds, kindOperations) {
+		data, err := input.client.Operation.GetWithContext(ctx, input.counter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batch, err := data.Batch()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		msgs = append(msgs, input.setMeta(batch, "operation")...)
+	}
+
+	input.done = true
+
+	ack := func(context.Context, error) error { return nil }
+
+	return msgs, ack, nil
+}
+
+func (input *benthosInput) Close(ctx context.Context) error {
+	return nil
+}
+
+func (input *benthosInput) setMeta(msgs service.MessageBatch, kind string) service.MessageBatch {
+	for _, msg := range msgs {
+		msg.MetaSetMut("kind", kind)
+		msg.MetaSetMut("counter_id", strconv.Itoa(input.counter))
+	}
+
+	return msgs
+}
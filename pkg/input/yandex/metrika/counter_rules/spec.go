@@ -0,0 +1,24 @@
+package counter_rules
+
+import "github.com/redpanda-data/benthos/v4/public/service"
+
+func inputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an input that fetch Yandex.Metrika API counter filters and operations.").
+		Description("Emits a message per traffic filter and operation of the counter. The `kind` metadata field is either `filter` or `operation`.").
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewIntField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example(44147844),
+			service.NewStringListField("kinds").
+				Description("Kinds of the counter rules to fetch.").
+				Default([]string{kindFilters, kindOperations}).
+				LintRule(`root = if this.type() == "string" && !["filters", "operations"].contains(this) { ["unknown kind %q".format(this)] }`),
+		)
+}
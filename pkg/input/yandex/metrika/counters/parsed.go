@@ -0,0 +1,61 @@
+package counters
+
+import (
+	"github.com/Jeffail/shutdown"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func inputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+	input := &benthosInput{
+		query:   &api.CountersQuery{},
+		logger:  mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	var err error
+
+	if conf.Contains("token") {
+		input.token, err = conf.FieldString("token")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("permission") {
+		input.query.Permission, err = conf.FieldString("permission")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("status") {
+		input.query.Status, err = conf.FieldString("status")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("search_string") {
+		input.query.SearchString, err = conf.FieldString("search_string")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("label_id") {
+		input.query.LabelID, err = conf.FieldInt("label_id")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("field") {
+		input.query.Field, err = conf.FieldStringList("field")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,37 @@
+package counters
+
+import "github.com/redpanda-data/benthos/v4/public/service"
+
+func inputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an input that fetch Yandex.Metrika API counters.").
+		Description("Emits a message per counter available to the token. Pages of the counters list are fetched until the end.").
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewStringEnumField("permission", "own", "view", "edit").
+				Description("Filter counters by the access level.").
+				Example("own").
+				Optional(),
+			service.NewStringEnumField("status", "Active", "Deleted").
+				Description("Filter counters by the status.").
+				Example("Active").
+				Optional(),
+			service.NewStringField("search_string").
+				Description("Filter counters by a substring of the ID, name, site or mirrors.").
+				Example("example.com").
+				Optional(),
+			service.NewIntField("label_id").
+				Description("Filter counters by the label ID.").
+				Optional(),
+			service.NewStringListField("field").
+				Description("Optional counter fields to include.").
+				Example([]string{"goals", "mirrors2"}).
+				Optional().
+				LintRule(`root = if this.type() == "string" && !["goals", "mirrors2", "grants", "filters", "operations", "labels"].contains(this) { ["unknown counter field %q".format(this)] }`),
+		)
+}
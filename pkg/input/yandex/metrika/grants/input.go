@@ -0,0 +1,131 @@
+package grants
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchInput(
+		"yandex_metrika_grants",
+		inputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return inputFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type benthosInput struct {
+	token     string
+	counters  []int
+	done      bool
+	client    *api.Client
+	logger    *service.Logger
+	shutSig   *shutdown.Signaller
+	clientMut sync.Mutex
+}
+
+func (input *benthosInput) Connect(ctx context.Context) error {
+	input.clientMut.Lock()
+	defer input.clientMut.Unlock()
+
+	if input.client != nil {
+		return nil
+	}
+
+	apiClient := api.NewClient(
+		apiKind,
+		apiVersion,
+		input.token,
+		input.logger,
+	)
+
+	// the client is kept only when the counters are resolved, so a failed connect is retried
+	if len(input.counters) == 0 {
+		data, err := apiClient.Counter.GetWithContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		var counters []int
+
+		for _, c := range data.Data {
+			// grants of deleted counters can't be read
+			if c.Status == api.CounterStatusDeleted {
+				continue
+			}
+
+			counters = append(counters, int(c.Id))
+		}
+
+		input.counters = counters
+
+		input.logger.With("counters", len(input.counters)).Debug("counters are resolved")
+	}
+
+	input.client = apiClient
+
+	return nil
+}
+
+func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	input.clientMut.Lock()
+	defer input.clientMut.Unlock()
+
+	if input.done {
+		return nil, nil, service.ErrEndOfInput
+	}
+
+	input.logger.Info("Fetch Yandex.Metrika API data")
+
+	var msgs service.MessageBatch
+
+	for _, counter := range input.counters {
+		data, err := input.client.Grant.GetWithContext(ctx, counter)
+		if err != nil {
+			var apiErr *api.APIError
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+				input.logger.With("counter_id", counter, "error", apiErr.Message).Warn("counter grants are not available")
+
+				continue
+			}
+
+			return nil, nil, err
+		}
+
+		batch, err := data.Batch()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, msg := range batch {
+			msg.MetaSetMut("counter_id", strconv.Itoa(counter))
+		}
+
+		msgs = append(msgs, batch...)
+	}
+
+	input.done = true
+
+	ack := func(context.Context, error) error { return nil }
+
+	return msgs, ack, nil
+}
+
+func (input *benthosInput) Close(ctx context.Context) error {
+	return nil
+}
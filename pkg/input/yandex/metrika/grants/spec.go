@@ -0,0 +1,22 @@
+package grants
+
+import "github.com/redpanda-data/benthos/v4/public/service"
+
+func inputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an input that fetch Yandex.Metrika API counter access grants.").
+		Description(`Emits a message per access grant of the counters. The `+"`counter_id`"+` metadata field contains the counter ID.
+Counters without access to the grants are skipped with a warning.`).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewIntListField("counter_ids").
+				Description("Yandex.Metrika Counter IDs. All counters available to the token are used if empty, except the deleted ones.").
+				Example([]int{44147844}).
+				Default([]int{}),
+		)
+}
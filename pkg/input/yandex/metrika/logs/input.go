@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/csv"
 	"io"
+	"slices"
 	"sync"
 	"time"
 
@@ -18,6 +19,12 @@ const (
 	apiVersion = "v1"
 )
 
+// dateFields maps log sources to their date fields used for the settle partitions.
+var dateFields = map[string]string{
+	"visits": "ym:s:date",
+	"hits":   "ym:pv:date",
+}
+
 func init() {
 	err := service.RegisterBatchInput(
 		"yandex_metrika_logs",
@@ -31,17 +38,20 @@ func init() {
 }
 
 type benthosInput struct {
-	token     string
-	counter   int
-	done      bool
-	part      int
-	query     *api.LogRequestQuery
-	eval      *api.EvalLogRequestResponseEntry
-	request   *api.LogRequestResponseEntry
-	client    *api.Client
-	logger    *service.Logger
-	shutSig   *shutdown.Signaller
-	clientMut sync.Mutex
+	token      string
+	counter    int
+	done       bool
+	part       int
+	settleDays int
+	dateField  string
+	dates      map[string]struct{} // dates are the settle partitions with rows.
+	query      *api.LogRequestQuery
+	eval       *api.EvalLogRequestResponseEntry
+	request    *api.LogRequestResponseEntry
+	client     *api.Client
+	logger     *service.Logger
+	shutSig    *shutdown.Signaller
+	clientMut  sync.Mutex
 }
 
 func (input *benthosInput) Connect(ctx context.Context) error {
@@ -180,6 +190,8 @@ func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch,
 			return nil, nil, err
 		}
 
+		dateIdx := slices.Index(csvHeader, input.dateField)
+
 		var rowNumber uint64 = 1
 
 		for {
@@ -209,6 +221,12 @@ func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch,
 			msg.MetaSetMut("current_row", rowNumber)
 			msg.MetaSetMut("query", query)
 
+			if input.settleDays > 0 && dateIdx >= 0 {
+				msg.MetaSetMut("replace_partition", utils.ReplacePartition(input.counter, csvRow[dateIdx]))
+
+				input.dates[csvRow[dateIdx]] = struct{}{}
+			}
+
 			msgs = append(msgs, msg)
 
 			rowNumber++
@@ -221,6 +239,18 @@ func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch,
 		input.part++
 
 		input.done = input.part == len(input.request.Parts)
+
+		if input.done && input.settleDays > 0 && dateIdx >= 0 {
+			// the rows of the part are emitted even if the markers can't be built
+			empty, err := input.emptyPartitions(query)
+			if err != nil {
+				input.logger.
+					With("counter_id", input.counter, "error", err).
+					Warn("can't emit empty settle partitions")
+			}
+
+			msgs = append(msgs, empty...)
+		}
 	}
 
 	ack := func(context.Context, error) error { return nil }
@@ -228,6 +258,38 @@ func (input *benthosInput) ReadBatch(ctx context.Context) (service.MessageBatch,
 	return msgs, ack, nil
 }
 
+// emptyPartitions returns the markers of the settle dates without rows,
+// so the stale rows of the dates are replaced downstream.
+func (input *benthosInput) emptyPartitions(query map[string]any) (service.MessageBatch, error) {
+	dates, err := utils.DateRange(input.query.Date1, input.query.Date2)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs service.MessageBatch
+
+	for _, date := range dates {
+		if _, ok := input.dates[date]; ok {
+			continue
+		}
+
+		msg := utils.EmptyPartitionMessage(input.counter, date)
+		msg.MetaSetMut("counter_id", input.counter)
+		msg.MetaSetMut("request_id", input.request.RequestID)
+		msg.MetaSetMut("query", query)
+
+		msgs = append(msgs, msg)
+	}
+
+	if len(msgs) > 0 {
+		input.logger.
+			With("counter_id", input.counter, "dates", len(msgs)).
+			Debug("emit empty settle partitions")
+	}
+
+	return msgs, nil
+}
+
 func (input *benthosInput) Close(ctx context.Context) error {
 	input.clientMut.Lock()
 	defer input.clientMut.Unlock()
@@ -1,6 +1,8 @@
 package logs
 
 import (
+	"slices"
+
 	"github.com/Jeffail/shutdown"
 	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/utils"
 	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
@@ -73,5 +75,24 @@ func inputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (servic
 		}
 	}
 
+	input.settleDays, err = conf.FieldInt("settle_days")
+	if err != nil {
+		return nil, err
+	}
+
+	if input.settleDays > 0 {
+		input.query.Date1, err = utils.SettleDate(input.query.Date1, input.settleDays)
+		if err != nil {
+			return nil, err
+		}
+
+		input.dateField = dateFields[input.query.Source]
+		input.dates = map[string]struct{}{}
+
+		if !slices.Contains(input.query.Fields, input.dateField) {
+			input.query.Fields = append(input.query.Fields, input.dateField)
+		}
+	}
+
 	return input, nil
 }
@@ -32,5 +32,9 @@ func inputConfig() *service.ConfigSpec {
 				Description("Attribution model.").
 				Example("LASTSIGN").
 				Optional(),
+			service.NewIntField("settle_days").
+				Description("Re-fetch N days before `date1` as the recent data keeps changing. The `ym:s:date` or `ym:pv:date` field is added to `fields` if missing and each row gets the `replace_partition` metadata field with the `counter_id` and `date` keys, so sinks can replace the partition atomically. A date without rows gets an empty object message with the `row_type` metadata field set to `empty`, so its stale rows are replaced too.").
+				Default(0).
+				LintRule(`root = if this < 0 { ["settle_days must not be negative"] }`),
 		)
 }
@@ -1,7 +1,11 @@
 package metrika
 
 import (
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/counter_rules"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/counters"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/goals"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/grants"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/logs"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/segments"
 	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/stat_table"
 )
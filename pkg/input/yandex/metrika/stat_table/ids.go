@@ -0,0 +1,42 @@
+package stat_table
+
+import (
+	"context"
+	"errors"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+)
+
+// resolveIDs fetches the counters list and returns IDs of the counters matching the selector.
+// Deleted counters are skipped.
+func resolveIDs(ctx context.Context, client *api.Client, s *stattable.Selector) ([]int, error) {
+	data, err := client.Counter.GetWithContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]stattable.Entry, 0, len(data.Data))
+
+	for _, c := range data.Data {
+		labels := make([]string, 0, len(c.Labels))
+		for _, l := range c.Labels {
+			labels = append(labels, l.Name)
+		}
+
+		entries = append(entries, stattable.Entry{
+			ID:      int(c.Id),
+			Name:    c.Name,
+			Labels:  labels,
+			Deleted: c.Status == api.CounterStatusDeleted,
+		})
+	}
+
+	ids := s.Select(entries)
+
+	if len(ids) == 0 {
+		return nil, errors.New("no counters match the ids selector")
+	}
+
+	return ids, nil
+}
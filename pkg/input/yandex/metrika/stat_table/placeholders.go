@@ -0,0 +1,144 @@
+package stat_table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+)
+
+// Supported placeholders.
+const (
+	placeholderGoalID      = "<goal_id>"
+	placeholderAttribution = "<attribution>"
+	placeholderCurrency    = "<currency>"
+)
+
+var errGoalCounters = errors.New("the <goal_id> placeholder requires placeholders.goal_ids, fan_out or settle_days to query several counters")
+
+// goalsFunc fetches the goals of the counter.
+type goalsFunc func(ctx context.Context, counter int) (*api.GoalsResponse, error)
+
+// placeholders expands parametrized metrics and dimensions.
+type placeholders struct {
+	goalIDs      []int
+	goalName     *regexp.Regexp
+	attributions []string
+	currencies   []string
+	fetchGoals   goalsFunc        // fetchGoals is set on connect.
+	goals        map[int][]string // goals is a cache of resolved goal IDs per counter.
+}
+
+// Expand replaces placeholders in names with all their values.
+// Names without placeholders are returned as is. Duplicates are removed.
+// Goals of a counter are not valid for the others, so without the configured goal IDs
+// the `<goal_id>` placeholder is expanded for a single counter only.
+func (p *placeholders) Expand(ctx context.Context, counters []int, names []string) ([]string, error) {
+	expanded := make([]string, 0, len(names))
+
+	for _, name := range names {
+		values := []string{name}
+
+		for _, ph := range []string{placeholderGoalID, placeholderAttribution, placeholderCurrency} {
+			if !strings.Contains(name, ph) {
+				continue
+			}
+
+			replacements, err := p.values(ctx, counters, ph)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(replacements) == 0 {
+				return nil, fmt.Errorf("no values for the %s placeholder in %q", ph, name)
+			}
+
+			next := make([]string, 0, len(values)*len(replacements))
+
+			for _, v := range values {
+				for _, r := range replacements {
+					next = append(next, strings.ReplaceAll(v, ph, r))
+				}
+			}
+
+			values = next
+		}
+
+		for _, v := range values {
+			if !slices.Contains(expanded, v) {
+				expanded = append(expanded, v)
+			}
+		}
+	}
+
+	return expanded, nil
+}
+
+// values returns the values of the placeholder.
+func (p *placeholders) values(ctx context.Context, counters []int, ph string) ([]string, error) {
+	switch ph {
+	case placeholderGoalID:
+		return p.goalValues(ctx, counters)
+	case placeholderAttribution:
+		return p.attributions, nil
+	case placeholderCurrency:
+		return p.currencies, nil
+	default:
+		return nil, fmt.Errorf("unknown placeholder %s", ph)
+	}
+}
+
+// goalValues returns the configured goal IDs or fetches the counter goals
+// filtered by the goal name pattern.
+func (p *placeholders) goalValues(ctx context.Context, counters []int) ([]string, error) {
+	if len(p.goalIDs) > 0 {
+		goals := make([]string, 0, len(p.goalIDs))
+
+		for _, id := range p.goalIDs {
+			goals = append(goals, strconv.Itoa(id))
+		}
+
+		return goals, nil
+	}
+
+	if len(counters) != 1 {
+		return nil, errGoalCounters
+	}
+
+	counter := counters[0]
+
+	if goals, ok := p.goals[counter]; ok {
+		return goals, nil
+	}
+
+	data, err := p.fetchGoals(ctx, counter)
+	if err != nil {
+		return nil, err
+	}
+
+	goals := make([]string, 0, len(data.Data))
+
+	for _, goal := range data.Data {
+		if p.goalName != nil && !p.goalName.MatchString(goal.Name) {
+			continue
+		}
+
+		id := strconv.FormatUint(goal.Id, 10)
+		if !slices.Contains(goals, id) {
+			goals = append(goals, id)
+		}
+	}
+
+	if p.goals == nil {
+		p.goals = make(map[int][]string)
+	}
+
+	p.goals[counter] = goals
+
+	return goals, nil
+}
@@ -0,0 +1,109 @@
+package stat_table
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceholders_Expand(t *testing.T) {
+	goals := map[int][]api.GoalsResponseEntry{
+		1: {{Id: 11, Name: "Order"}, {Id: 12, Name: "Call"}},
+		2: {{Id: 21, Name: "Order"}},
+	}
+
+	fetchGoals := func(_ context.Context, counter int) (*api.GoalsResponse, error) {
+		return &api.GoalsResponse{Data: goals[counter]}, nil
+	}
+
+	testCases := []struct {
+		name          string
+		placeholders  placeholders
+		counters      []int
+		names         []string
+		expected      []string
+		expectedError error
+	}{
+		{
+			name:         "Names without placeholders",
+			placeholders: placeholders{},
+			counters:     []int{1, 2},
+			names:        []string{"ym:s:visits", "ym:s:users"},
+			expected:     []string{"ym:s:visits", "ym:s:users"},
+		},
+		{
+			name:         "Counter goals",
+			placeholders: placeholders{},
+			counters:     []int{1},
+			names:        []string{"ym:s:visits", "ym:s:goal<goal_id>reaches"},
+			expected:     []string{"ym:s:visits", "ym:s:goal11reaches", "ym:s:goal12reaches"},
+		},
+		{
+			name:         "Counter goals filtered by name",
+			placeholders: placeholders{goalName: regexp.MustCompile("^Order")},
+			counters:     []int{2},
+			names:        []string{"ym:s:goal<goal_id>reaches"},
+			expected:     []string{"ym:s:goal21reaches"},
+		},
+		{
+			name:          "Goals of several counters",
+			placeholders:  placeholders{},
+			counters:      []int{1, 2},
+			names:         []string{"ym:s:goal<goal_id>reaches"},
+			expectedError: errGoalCounters,
+		},
+		{
+			name:         "Configured goals of several counters",
+			placeholders: placeholders{goalIDs: []int{5}},
+			counters:     []int{1, 2},
+			names:        []string{"ym:s:goal<goal_id>reaches"},
+			expected:     []string{"ym:s:goal5reaches"},
+		},
+		{
+			name: "Several placeholders",
+			placeholders: placeholders{
+				goalIDs:      []int{5},
+				attributions: []string{"lastsign", "first"},
+				currencies:   []string{"RUB"},
+			},
+			counters: []int{1},
+			names: []string{
+				"ym:s:goal<goal_id><currency>ConvertedRevenue",
+				"ym:s:<attribution>TrafficSource",
+				"ym:s:lastsignTrafficSource",
+			},
+			expected: []string{
+				"ym:s:goal5RUBConvertedRevenue",
+				"ym:s:lastsignTrafficSource",
+				"ym:s:firstTrafficSource",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.placeholders
+			p.fetchGoals = fetchGoals
+
+			actual, err := p.Expand(context.Background(), tc.counters, tc.names)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestPlaceholders_ExpandNoValues(t *testing.T) {
+	p := placeholders{}
+
+	_, err := p.Expand(context.Background(), []int{1}, []string{"ym:s:<attribution>TrafficSource"})
+	assert.Error(t, err)
+}
@@ -1,6 +1,11 @@
 package stat_table
 
-import "github.com/redpanda-data/benthos/v4/public/service"
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/catalog"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/filter"
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/stattable"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
 
 func inputConfig() *service.ConfigSpec {
 	return service.NewConfigSpec().
@@ -12,9 +17,12 @@ func inputConfig() *service.ConfigSpec {
 				Description("Yandex.Metrika API token").
 				Secret().
 				Optional(),
-			service.NewIntListField("ids").
-				Description("Yandex.Metrika Counter IDs").
-				Example([]int{44147844, 2215573}),
+			service.NewAnyField("ids").
+				Description("Yandex.Metrika Counter IDs, `all` to read all counters available to the token or an object with the `name` and `label` regular expressions to select counters by name and label. Counters are resolved on connect, deleted counters are skipped.").
+				Example([]int{44147844, 2215573}).
+				Example("all").
+				Example(map[string]any{"label": "^prod$"}).
+				LintRule(stattable.IDsLintRule("counter")),
 			service.NewStringListField("metrics").
 				Description("A list of metrics.").
 				Example([]string{"ym:s:pageviews", "ym:s:visits", "ym:s:users"}),
@@ -32,12 +40,27 @@ func inputConfig() *service.ConfigSpec {
 			service.NewStringField("filters").
 				Description("Segmentation filter.").
 				Optional(),
+			service.NewAnyField("filter").
+				Description("Structured segmentation filter, an alternative to `filters`. A node has exactly one of the `and`, `or`, `not` or `field` keys. A `field` node has the `op` key (`eq`, `ne`, `gt`, `ge`, `lt`, `le`, `contains`, `not_contains`, `regex`, `not_regex`, `starts_with`, `not_starts_with`, `in`, `not_in`) and the `value` key, a list for the `in` and `not_in` ops.").
+				Example(map[string]any{
+					"and": []any{
+						map[string]any{"field": "ym:s:lastTrafficSource", "op": "eq", "value": "direct"},
+						map[string]any{"field": "ym:s:regionCountry", "op": "in", "value": []any{"225", "187"}},
+					},
+				}).
+				Optional().
+				LintRule(filter.LintRule),
 			service.NewStringListField("sort").
 				Description("A list of dimensions and metrics to use for sorting.").
 				Optional(),
 			service.NewStringField("accuracy").
-				Description("Sample size for the report.").
-				Optional(),
+				Description("Sample size for the report: `low`, `medium`, `high`, `full` or a number in the (0; 1] range.").
+				Examples("full", "0.1").
+				Optional().
+				LintRule(`root = if !["low", "medium", "high", "full"].contains(this) && (this.number().catch(-1) <= 0 || this.number().catch(-1) > 1) { ["accuracy must be one of low, medium, high, full or a number in the (0; 1] range"] }`),
+			service.NewBoolField("proposed_accuracy").
+				Description("Let the API choose the accuracy to return the report faster.").
+				Default(false),
 			service.NewStringField("lang").
 				Description("Language.").
 				Example("en").
@@ -50,8 +73,135 @@ func inputConfig() *service.ConfigSpec {
 				Description("Time zone in ±hh:mm format within the range of [-23:59; +23:59]").
 				Example("+03:00").
 				Optional(),
+			service.NewObjectField("placeholders",
+				service.NewIntListField("goal_ids").
+					Description("Goal IDs for the `<goal_id>` placeholder. If not set, all goals of each counter are used, which requires `fan_out` or `settle_days` to query several counters.").
+					Example([]int{123456, 654321}).
+					Optional(),
+				service.NewStringField("goal_name").
+					Description("A regular expression to filter goals by name for the `<goal_id>` placeholder.").
+					Example("^Order").
+					Optional(),
+				service.NewStringListField("attributions").
+					Description("Attribution models for the `<attribution>` placeholder.").
+					Example([]string{"lastsign", "first"}).
+					Default([]string{}),
+				service.NewStringListField("currencies").
+					Description("Currencies for the `<currency>` placeholder.").
+					Example([]string{"RUB", "USD"}).
+					Default([]string{}),
+			).
+				Description("Values for the parametrized metrics and dimensions, e.g. `ym:s:goal<goal_id>reaches` or `ym:s:<attribution>TrafficSource`. Goals are fetched with the Management API.").
+				Optional(),
+			service.NewBoolField("fan_out").
+				Description("Issue a separate query per counter instead of aggregating all counters in one report. Each row gets the `counter_id` field and metadata field.").
+				Default(false),
+			service.NewIntField("fan_out_concurrency").
+				Description("Maximum number of concurrent queries in the fan-out mode.").
+				Default(3).
+				LintRule(`root = if this < 1 { ["field must be greater than 0"] }`),
+			stattable.MaxMetricsField(maxMetrics),
+			service.NewStringField("currency").
+				Description("Currency for money metrics as ISO 4217 code.").
+				Example("RUB").
+				Optional().
+				LintRule(`root = if !this.re_match("^[A-Z]{3}$") { ["currency must be a three-letter ISO 4217 code"] }`),
+			service.NewBoolField("include_undefined").
+				Description("Include rows with undefined dimension values.").
+				Default(false),
+			service.NewStringEnumField("group", "all", "auto", "minute", "dekaminute", "hour", "hours", "day", "week", "month", "quarter", "year").
+				Description("Time grouping for the time dimensions.").
+				Example("day").
+				Optional(),
+			service.NewIntField("quantile").
+				Description("Quantile in percents for the quantile metrics.").
+				Example(90).
+				Optional().
+				LintRule(`root = if this <= 0 || this > 100 { ["quantile must be in the (0; 100] range"] }`),
+			service.NewStringEnumField("attribution", "first", "last", "lastsign", "last_yandex_direct_click", "cross_device_last_significant", "cross_device_first", "cross_device_last_yandex_direct_click", "cross_device_last", "automatic").
+				Description("Attribution model for the `<attribution>` parametrized fields.").
+				Example("lastsign").
+				Optional(),
+			service.NewBoolField("pretty").
+				Description("Request a formatted API response.").
+				Default(false),
 			service.NewStringListField("direct_client_logins").
 				Description("A list of usernames of Yandex Direct clients").
 				Optional(),
-		)
+			service.NewStringEnumField("format", "json", "csv").
+				Description("Response format. The `csv` format streams rows from the `data.csv` endpoint and is cheaper to decode for large reports, but it does not provide the total rows number.").
+				Default("json"),
+			service.NewStringAnnotatedEnumField("dimension_ids", map[string]string{
+				"none":   "Emit dimension names only.",
+				"field":  "Emit dimension IDs as separate `<dimension>_id` fields.",
+				"object": "Emit dimensions as objects with `name` and `id` fields.",
+			}).
+				Description("How to emit dimension IDs. Dimension names are localized and may change over time, IDs are stable. Not supported by the `csv` format.").
+				Default("none"),
+			service.NewStringAnnotatedEnumField("shape", map[string]string{
+				"wide":  "One message per row with dimensions and metrics as fields.",
+				"long":  "One message per row and metric with dimensions and the `metric` and `value` fields.",
+				"table": "The whole report as one message with the `rows` array and the optional `totals` object.",
+			}).
+				Description("Shape of the report messages.").
+				Default("wide"),
+			service.NewStringAnnotatedEnumField("key_strategy", map[string]string{
+				"raw":      "Keep API field names as is, e.g. `ym:s:lastTrafficSource`.",
+				"stripped": "Remove the namespace prefix, e.g. `lastTrafficSource`.",
+				"snake":    "Remove the namespace prefix and convert to snake case, e.g. `last_traffic_source`.",
+				"alias":    "Use `key_aliases` and keep other names as is.",
+			}).
+				Description("How to convert metric and dimension names to message keys.").
+				Default("snake"),
+			service.NewStringMapField("key_aliases").
+				Description("Custom message keys for metrics and dimensions. Aliases take precedence over `key_strategy`.").
+				Example(map[string]string{"ym:s:visits": "sessions"}).
+				Optional(),
+			service.NewBoolField("typed_metrics").
+				Description("Use the metric types of the built-in metrics catalog to emit counter metrics such as visits or users as integers. The `metric_types` metadata field maps metric keys to `integer`, `float`, `percent`, `duration` or `currency` types. Metrics missing from the catalog keep float values and have no type.").
+				Default(false),
+			service.NewBoolField("emit_totals").
+				Description("Emit a separate message with the report totals. The message has the `row_type` metadata field set to `totals`. Not supported by the `csv` format.").
+				Default(false),
+			service.NewBoolField("fail_on_sampled").
+				Description("Fail the report with an error if the report data is sampled. A failed report is read again after the input reconnects. Not supported by the `csv` format.").
+				Default(false),
+			service.NewBoolField("require_unsampled").
+				Description("Bisect the `date1`-`date2` range until each sub-report is not sampled at the requested `accuracy`, then read the sub-reports one by one. Each row gets the `date1` and `date2` metadata fields with its effective date window.").
+				Default(false),
+			service.NewIntField("settle_days").
+				Description("Re-fetch N days before `date1` as the recent data keeps changing. The report is read per counter and day, each row gets the `counter_id` field and the `replace_partition` metadata field with the `counter_id` and `date` keys, so sinks can replace the partition atomically. A partition without rows is emitted as a single empty message with the `row_type` metadata field set to `empty`, so sinks can clear it.").
+				Default(0).
+				LintRule(`root = if this < 0 { ["settle_days must not be negative"] }`),
+			service.NewObjectField("diff",
+				service.NewStringField("cache").
+					Description("A cache resource to store row hashes between polls. Hashes are stored when the rows are delivered, so undelivered rows are emitted again by the next poll."),
+				service.NewStringField("key_prefix").
+					Description("Prefix of the cache keys. Set it to share one cache resource between several inputs.").
+					Default(""),
+				service.NewDurationField("ttl").
+					Description("TTL of the cache keys.").
+					Example("24h").
+					Optional(),
+				service.NewBoolField("emit_deleted").
+					Description("Emit tombstones for rows missing from the current poll. The tombstone has the dimension values only.").
+					Default(false),
+			).
+				Description("Emit only new or changed rows comparing hashes of rows keyed by their dimension values with the previous poll. Each row gets the `change` metadata field set to `new`, `changed` or `deleted`. Not supported by the `table` shape.").
+				Optional(),
+			service.NewBoolField("catalog_validation").
+				Description("Reject metrics and dimensions missing from the built-in catalog at lint time. Disable it to query the API fields the catalog does not know yet.").
+				Default(true),
+		).
+		LintRule(stattable.LintRule(catalog.Metrika, maxMetrics, lintRules...))
+}
+
+// maxMetrics is the metrics limit of a query.
+const maxMetrics = 20
+
+// lintRules check the filters, the diff mode and the goal placeholders.
+var lintRules = []string{
+	`if this.exists("filter") && this.exists("filters") { ["both filter and filters can't be set simultaneously"] } else { [] }`,
+	`if this.exists("diff") && this.shape.or("wide") == "table" { ["diff can't be used with the table shape"] } else { [] }`,
+	`if [$fields, this.sort.or([])].flatten().any(n -> n.string().contains("<goal_id>")) && this.placeholders.goal_ids.or([]).length() == 0 && !this.fan_out.or(false) && this.settle_days.or(0) == 0 && !(this.ids.type() == "array" && this.ids.length() == 1) { ["the <goal_id> placeholder requires placeholders.goal_ids, fan_out or settle_days to query several counters"] } else { [] }`,
 }
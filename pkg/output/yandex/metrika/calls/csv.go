@@ -0,0 +1,129 @@
+package calls
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// callColumn is a CSV column of the calls upload.
+type callColumn struct {
+	name        string // name is the CSV column name.
+	field       string // field is the config field of the column mapping.
+	mapping     string // mapping is the default mapping of a required column.
+	description string
+}
+
+// callColumns are the supported CSV columns. The ID column name depends on the client ID type.
+var callColumns = []callColumn{
+	{name: "ClientId", field: "id", mapping: "this.client_id", description: " The column is named `UserId` for the `USER_ID` client ID type."},
+	{name: "DateTime", field: "date_time", mapping: "this.date_time", description: " A Unix timestamp or an RFC 3339 string."},
+	{name: "PhoneNumber", field: "phone_number", mapping: "this.phone_number"},
+	{name: "TalkDuration", field: "talk_duration", mapping: "this.talk_duration", description: " Call duration in seconds."},
+	{name: "HoldDuration", field: "hold_duration", mapping: "this.hold_duration", description: " Hold duration in seconds."},
+	{name: "CallMissed", field: "call_missed", mapping: "this.call_missed", description: " Whether the call is missed."},
+	{name: "Tag", field: "tag"},
+	{name: "FirstTimeCaller", field: "first_time_caller", description: " Whether the caller is new."},
+	{name: "URL", field: "url", description: " URL of the page the call is made from."},
+	{name: "CallTrackerURL", field: "call_tracker_url", description: " URL of the call in the call tracker."},
+}
+
+// column is a configured CSV column.
+type column struct {
+	name string
+	exec *bloblang.Executor
+}
+
+// encodeCSV converts the messages into the calls CSV file using the column mappings.
+func encodeCSV(columns []column, msgs service.MessageBatch) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.name)
+	}
+
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for i := range msgs {
+		record := make([]string, 0, len(columns))
+
+		for _, c := range columns {
+			value, err := columnValue(msgs, i, c)
+			if err != nil {
+				return nil, err
+			}
+
+			record = append(record, value)
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func columnValue(msgs service.MessageBatch, i int, c column) (string, error) {
+	res, err := msgs.BloblangQuery(i, c.exec)
+	if err != nil {
+		return "", fmt.Errorf("%s column mapping: %w", c.name, err)
+	}
+
+	if res == nil {
+		return "", nil
+	}
+
+	// string and null results are stored as raw bytes
+	var v any
+
+	if res.HasStructured() {
+		if v, err = res.AsStructured(); err != nil {
+			return "", fmt.Errorf("%s column mapping: %w", c.name, err)
+		}
+	} else {
+		b, err := res.AsBytes()
+		if err != nil {
+			return "", fmt.Errorf("%s column mapping: %w", c.name, err)
+		}
+
+		if s := string(b); s != "null" {
+			v = s
+		}
+	}
+
+	if c.name == "DateTime" {
+		ts, err := upload.FormatTime(v)
+		if err != nil {
+			return "", fmt.Errorf("DateTime column: %w", err)
+		}
+
+		return ts, nil
+	}
+
+	return formatValue(v), nil
+}
+
+// formatValue converts a column value into a CSV cell. Booleans are written as 1 and 0.
+func formatValue(v any) string {
+	if b, ok := v.(bool); ok {
+		if b {
+			return "1"
+		}
+
+		return "0"
+	}
+
+	return upload.FormatValue(v)
+}
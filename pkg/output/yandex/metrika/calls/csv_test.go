@@ -0,0 +1,104 @@
+package calls
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	newColumns := func(mappings ...string) []column {
+		columns := make([]column, 0, len(mappings)/2)
+
+		for i := 0; i < len(mappings); i += 2 {
+			exec, err := bloblang.Parse(mappings[i+1])
+			require.NoError(t, err)
+
+			columns = append(columns, column{name: mappings[i], exec: exec})
+		}
+
+		return columns
+	}
+
+	testCases := []struct {
+		name        string
+		columns     []column
+		rows        []any
+		expected    string
+		expectError bool
+	}{
+		{
+			name:    "Column order",
+			columns: newColumns("ClientId", "this.client_id", "DateTime", "this.date_time", "TalkDuration", "this.talk", "Tag", "this.tag"),
+			rows: []any{
+				map[string]any{"tag": "sales", "talk": 30.0, "date_time": 1704164645.0, "client_id": "123"},
+				map[string]any{"client_id": 456.0, "date_time": "1704164646"},
+			},
+			expected: "ClientId,DateTime,TalkDuration,Tag\n123,1704164645,30,sales\n456,1704164646,,\n",
+		},
+		{
+			name:    "Escaping and booleans",
+			columns: newColumns("UserId", "this.user_id", "DateTime", "this.date_time", "CallMissed", "this.missed", "FirstTimeCaller", "this.first"),
+			rows: []any{
+				map[string]any{"user_id": "a,\"b\"", "date_time": "1704164645", "missed": true, "first": false},
+			},
+			expected: "UserId,DateTime,CallMissed,FirstTimeCaller\n\"a,\"\"b\"\"\",1704164645,1,0\n",
+		},
+		{
+			name:    "Time formats",
+			columns: newColumns("DateTime", "this.date_time"),
+			rows: []any{
+				map[string]any{"date_time": "2024-01-02T06:04:05+03:00"},
+				map[string]any{"date_time": "1704164645"},
+				map[string]any{"date_time": 1704164645},
+			},
+			expected: "DateTime\n1704164645\n1704164645\n1704164645\n",
+		},
+		{
+			name:    "Time mapping",
+			columns: newColumns("DateTime", `this.date.ts_parse("2006-01-02 15:04:05")`),
+			rows: []any{
+				map[string]any{"date": "2024-01-02 03:04:05"},
+			},
+			expected: "DateTime\n1704164645\n",
+		},
+		{
+			name:        "Invalid time",
+			columns:     newColumns("DateTime", "this.date_time"),
+			rows:        []any{map[string]any{"date_time": "2024-01-02"}},
+			expectError: true,
+		},
+		{
+			name:        "Missing time",
+			columns:     newColumns("DateTime", "this.date_time"),
+			rows:        []any{map[string]any{}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs := make(service.MessageBatch, 0, len(tc.rows))
+
+			for _, row := range tc.rows {
+				msg := service.NewMessage(nil)
+				msg.SetStructured(row)
+				msgs = append(msgs, msg)
+			}
+
+			data, err := encodeCSV(tc.columns, msgs)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
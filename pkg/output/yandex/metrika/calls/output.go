@@ -0,0 +1,161 @@
+package calls
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_calls",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type benthosOutput struct {
+	token        string
+	query        *api.CallsQuery
+	columns      []column
+	wait         bool
+	waitInterval time.Duration
+	waitTimeout  time.Duration
+	writer       *upload.Writer
+	client       *api.Client
+	logger       *service.Logger
+	clientMut    sync.Mutex
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client != nil {
+		return nil
+	}
+
+	output.client = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	)
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client == nil {
+		return service.ErrNotConnected
+	}
+
+	return upload.WriteBatch(ctx, output.writer, batch,
+		func(msgs service.MessageBatch) (struct{}, []byte, error) {
+			data, err := encodeCSV(output.columns, msgs)
+
+			return struct{}{}, data, err
+		},
+		output.upload,
+	)
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+func (output *benthosOutput) upload(ctx context.Context, counter int, msgs service.MessageBatch, _ struct{}, data []byte) (upload.Result, error) {
+	resp, err := output.client.OfflineConversion.UploadCallsWithContext(ctx, counter, output.query, data)
+	if err != nil {
+		return upload.Result{}, err
+	}
+
+	uploading := resp.Uploading
+
+	output.logger.
+		With("counter_id", counter, "upload_id", uploading.Id, "rows", len(msgs)).
+		Info("calls are uploaded")
+
+	if !output.wait {
+		return uploadResult(uploading), nil
+	}
+
+	uploading, done, err := upload.Wait(
+		ctx,
+		output.waitInterval,
+		output.waitTimeout,
+		uploading,
+		func(u api.Uploading) bool { return u.IsDone() },
+		func(ctx context.Context) (api.Uploading, error) {
+			resp, err := output.client.OfflineConversion.GetCallsWithContext(ctx, counter, uploading.Id)
+			if err != nil {
+				return api.Uploading{}, err
+			}
+
+			output.logger.
+				With("counter_id", counter, "upload_id", resp.Uploading.Id, "status", resp.Uploading.Status).
+				Trace("update calls upload info")
+
+			return resp.Uploading, nil
+		},
+	)
+	if err != nil {
+		// the file is uploaded, so the batch is not retried
+		output.logger.
+			With("counter_id", counter, "upload_id", uploading.Id, "error", err).
+			Warn("can't wait for the calls upload processing")
+
+		return uploadResult(uploading), nil
+	}
+
+	log := output.logger.With("counter_id", counter, "upload_id", uploading.Id, "status", uploading.Status, "lines", uploading.LineQuantity)
+
+	switch {
+	case !done:
+		log.Warn("calls upload is not processed in time")
+	case uploading.Status == api.UploadStatusLinkageFailure:
+		// the failed processing is not fixed by a retry of the same file
+		log.Error("calls upload is failed")
+	default:
+		log.Info("calls upload is processed")
+	}
+
+	return uploadResult(uploading), nil
+}
+
+// uploadResult returns the result of the upload.
+func uploadResult(u api.Uploading) upload.Result {
+	return upload.Result{
+		ID:     strconv.FormatUint(u.Id, 10),
+		Status: u.Status,
+	}
+}
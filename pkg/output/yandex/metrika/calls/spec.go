@@ -0,0 +1,69 @@
+package calls
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputConfig() *service.ConfigSpec {
+	columns := make([]*service.ConfigField, 0, len(callColumns))
+
+	for _, c := range callColumns {
+		field := service.NewBloblangField(c.field).
+			Description("Mapping of the `" + c.name + "` column." + c.description)
+
+		if c.mapping != "" {
+			field = field.Default(c.mapping)
+		} else {
+			field = field.Optional()
+		}
+
+		columns = append(columns, field)
+	}
+
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that uploads phone calls to Yandex.Metrika API.").
+		Description(upload.Description(`Each batch of messages is uploaded as a CSV file per counter. The CSV columns are built with the Bloblang mappings of the `+"`columns`"+` field.
+Optional columns are omitted unless a mapping is set. Boolean values are written as 1 or 0.`)).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewStringEnumField("client_id_type", "CLIENT_ID", "USER_ID").
+				Description("Type of the visitor identifier.").
+				Default("CLIENT_ID"),
+			service.NewStringField("comment").
+				Description("Comment of the uploads.").
+				Optional(),
+			service.NewStringField("new_goal_name").
+				Description("Name of the goal created for the calls.").
+				Optional(),
+			service.NewIntField("new_calls_threshold").
+				Description("Number of days since the previous call a caller is considered new.").
+				Example(30).
+				Optional().
+				LintRule(`root = if this <= 0 { ["new_calls_threshold must be positive"] }`),
+			service.NewObjectField("columns", columns...).
+				Description("Bloblang mappings of the CSV columns."),
+			service.NewBoolField("wait").
+				Description("Wait for the upload processing. A failed processing is logged as an error and the messages are acknowledged, since a retry of the same file does not fix it.").
+				Default(false),
+			service.NewDurationField("wait_interval").
+				Description("Interval between the upload status checks.").
+				Default("30s"),
+			service.NewDurationField("wait_timeout").
+				Description("Maximum time to wait for the upload processing. The batch is acknowledged after the timeout.").
+				Default("30m"),
+			upload.SyncResponseField(),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
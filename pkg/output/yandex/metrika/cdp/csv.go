@@ -0,0 +1,67 @@
+package cdp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+)
+
+// encodeCSV converts the rows into a CSV file with the columns of the sorted row keys.
+func encodeCSV(rows []map[string]any) ([]byte, error) {
+	var columns []string
+
+	for _, row := range rows {
+		for k := range row {
+			if !slices.Contains(columns, k) {
+				columns = append(columns, k)
+			}
+		}
+	}
+
+	slices.Sort(columns)
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		record := make([]string, 0, len(columns))
+
+		for _, c := range columns {
+			value, err := formatValue(row[c])
+			if err != nil {
+				return nil, err
+			}
+
+			record = append(record, value)
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// formatValue formats a scalar value as is and encodes nested values as JSON.
+// formatValue converts a row value into a CSV cell. Nested objects and arrays are written as JSON.
+func formatValue(v any) (string, error) {
+	switch t := v.(type) {
+	case map[string]any, []any:
+		b, err := json.Marshal(t)
+
+		return string(b), err
+	default:
+		return upload.FormatValue(t), nil
+	}
+}
@@ -0,0 +1,56 @@
+package cdp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rows     []map[string]any
+		expected string
+	}{
+		{
+			name: "Sorted columns of all rows",
+			rows: []map[string]any{
+				{"id": "1", "revenue": 100.5, "client_ids": []any{"123", "456"}},
+				{"status": "PAID", "id": json.Number("2"), "paid": true},
+			},
+			expected: "client_ids,id,paid,revenue,status\n" +
+				"\"[\"\"123\"\",\"\"456\"\"]\",1,,100.5,\n" +
+				",2,true,,PAID\n",
+		},
+		{
+			name: "Escaping",
+			rows: []map[string]any{
+				{"id": "1", "comment": "line, \"quoted\"\nnext"},
+			},
+			expected: "comment,id\n\"line, \"\"quoted\"\"\nnext\",1\n",
+		},
+		{
+			name: "Nested object",
+			rows: []map[string]any{
+				{"id": "1", "products": map[string]any{"sku": "A-1", "qty": 2.0}},
+			},
+			expected: "id,products\n1,\"{\"\"qty\"\":2,\"\"sku\"\":\"\"A-1\"\"}\"\n",
+		},
+		{
+			name: "Create time",
+			rows: []map[string]any{
+				{"id": "1", "create_date_time": "2024-01-02 03:04:05"},
+			},
+			expected: "create_date_time,id\n2024-01-02 03:04:05,1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := encodeCSV(tc.rows)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
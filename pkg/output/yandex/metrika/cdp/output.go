@@ -0,0 +1,168 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "cdp/api"
+	apiVersion = "v1"
+)
+
+// Upload formats.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_cdp",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type benthosOutput struct {
+	token     string
+	entity    string
+	format    string
+	query     *api.CDPQuery
+	writer    *upload.Writer
+	client    *api.Client
+	logger    *service.Logger
+	clientMut sync.Mutex
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client != nil {
+		return nil
+	}
+
+	output.client = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	)
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client == nil {
+		return service.ErrNotConnected
+	}
+
+	return upload.WriteBatch(ctx, output.writer, batch, output.encode, output.upload)
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+// encode returns the rows of the messages and the uploaded data in the configured format.
+func (output *benthosOutput) encode(msgs service.MessageBatch) ([]map[string]any, []byte, error) {
+	rows := make([]map[string]any, 0, len(msgs))
+
+	for _, msg := range msgs {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		row, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil, errors.New("CDP message must be an object")
+		}
+
+		rows = append(rows, row)
+	}
+
+	if output.format == formatCSV {
+		data, err := encodeCSV(rows)
+
+		return rows, data, err
+	}
+
+	data, err := json.Marshal(rows)
+
+	return rows, data, err
+}
+
+// upload sends the rows of the counter and fails the messages of the rows rejected by the API.
+func (output *benthosOutput) upload(ctx context.Context, counter int, msgs service.MessageBatch, rows []map[string]any, data []byte) (upload.Result, error) {
+	resp, err := output.post(ctx, counter, rows, data)
+	if err != nil {
+		return upload.Result{}, err
+	}
+
+	output.logger.
+		With("counter_id", counter, "uploading_id", resp.UploadingID, "rows", len(rows), "errors", len(resp.Errors)).
+		Info("CDP data is uploaded")
+
+	res := upload.Result{ID: resp.UploadingID}
+
+	for _, rowErr := range resp.Errors {
+		log := output.logger.With("counter_id", counter, "uploading_id", resp.UploadingID, "row", rowErr.Row, "message", rowErr.Message)
+
+		if rowErr.Row < 0 || rowErr.Row >= len(msgs) {
+			log.Error("CDP upload error of an unknown row")
+
+			continue
+		}
+
+		log.Error("CDP row is rejected")
+
+		if res.Failed == nil {
+			res.Failed = map[int]error{}
+		}
+
+		res.Failed[rowErr.Row] = errors.New(rowErr.Message)
+	}
+
+	return res, nil
+}
+
+// post sends the rows in the configured format.
+func (output *benthosOutput) post(ctx context.Context, counter int, rows []map[string]any, data []byte) (*api.CDPUploadResponse, error) {
+	if output.format == formatCSV {
+		return output.client.CDP.UploadCSVWithContext(ctx, counter, output.entity, output.query, data)
+	}
+
+	items := make([]any, len(rows))
+	for i, row := range rows {
+		items[i] = row
+	}
+
+	return output.client.CDP.UploadJSONWithContext(ctx, counter, output.entity, output.query, items)
+}
@@ -0,0 +1,67 @@
+package cdp
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// entities maps the config entity types to the API entity types.
+var entities = map[string]string{
+	"orders":   api.CDPEntityOrders,
+	"contacts": api.CDPEntityContacts,
+}
+
+func outputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*benthosOutput, error) {
+	output := &benthosOutput{
+		query: &api.CDPQuery{},
+		writer: &upload.Writer{
+			Uploads: upload.NewTracker(upload.TrackerTTL),
+			Name:    "CDP data",
+			Logger:  mgr.Logger(),
+		},
+		logger: mgr.Logger(),
+	}
+
+	var err error
+
+	if conf.Contains("token") {
+		output.token, err = conf.FieldString("token")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.writer.Counter, err = conf.FieldInterpolatedString("counter_id")
+	if err != nil {
+		return nil, err
+	}
+
+	output.writer.SyncResponse, err = conf.FieldBool("sync_response")
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := conf.FieldString("entity")
+	if err != nil {
+		return nil, err
+	}
+
+	output.entity = entities[entity]
+
+	output.format, err = conf.FieldString("format")
+	if err != nil {
+		return nil, err
+	}
+
+	if output.format == formatCSV {
+		output.query.DelimiterType = "COMMA"
+	}
+
+	output.query.MergeMode, err = conf.FieldString("merge_mode")
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
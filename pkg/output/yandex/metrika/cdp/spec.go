@@ -0,0 +1,48 @@
+package cdp
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that uploads CRM orders or contacts to Yandex.Metrika CDP API.").
+		Description(upload.Description(`Each batch of messages is uploaded per counter. A message is an object of an order or a contact in the CDP API format.
+Rows rejected by the API are logged and reported as errors of the corresponding messages, the rest of the batch is acknowledged.
+The rejected rows of a counter are reported once, a retry of the batch does not report them again.`)).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewStringAnnotatedEnumField("entity", map[string]string{
+				"orders":   "Simple orders.",
+				"contacts": "Contacts.",
+			}).
+				Description("Type of the uploaded entities."),
+			service.NewStringAnnotatedEnumField("format", map[string]string{
+				"json": "Send a JSON document.",
+				"csv":  "Send a CSV file with the columns of the sorted message keys. Nested values are encoded as JSON.",
+			}).
+				Description("Format of the upload.").
+				Default("json"),
+			service.NewStringAnnotatedEnumField("merge_mode", map[string]string{
+				"APPEND": "Add new entities, keep the existing ones as is.",
+				"SAVE":   "Add new entities and update the existing ones.",
+				"UPDATE": "Update the existing entities only.",
+			}).
+				Description("Merge mode of the existing entities.").
+				Default("SAVE"),
+			upload.SyncResponseField(),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
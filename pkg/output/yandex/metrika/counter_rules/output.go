@@ -0,0 +1,296 @@
+package counter_rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_counter_rules",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Rule changes of the reconciliation.
+const (
+	actionCreate = "create"
+	actionDelete = "delete"
+)
+
+// statusActive is the default status of the desired rules.
+const statusActive = "active"
+
+// desiredState is a message of the desired counter rules. A nil list means the kind is not managed.
+type desiredState struct {
+	Filters    *[]api.FiltersResponseEntry    `json:"filters"`
+	Operations *[]api.OperationsResponseEntry `json:"operations"`
+}
+
+type benthosOutput struct {
+	token     string
+	counter   *service.InterpolatedString
+	dryRun    bool
+	client    *api.Client
+	logger    *service.Logger
+	clientMut sync.Mutex
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client != nil {
+		return nil
+	}
+
+	output.client = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	)
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client == nil {
+		return service.ErrNotConnected
+	}
+
+	for i, msg := range batch {
+		raw, err := batch.TryInterpolatedString(i, output.counter)
+		if err != nil {
+			return fmt.Errorf("counter_id interpolation: %w", err)
+		}
+
+		counter, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid counter_id %q: %w", raw, err)
+		}
+
+		b, err := msg.AsBytes()
+		if err != nil {
+			return err
+		}
+
+		var state desiredState
+
+		if err := json.Unmarshal(b, &state); err != nil {
+			return err
+		}
+
+		if state.Filters != nil {
+			if err := output.applyFilters(ctx, counter, *state.Filters); err != nil {
+				return err
+			}
+		}
+
+		if state.Operations != nil {
+			if err := output.applyOperations(ctx, counter, *state.Operations); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+func (output *benthosOutput) applyFilters(ctx context.Context, counter int, desired []api.FiltersResponseEntry) error {
+	data, err := output.client.Filter.GetWithContext(ctx, counter)
+	if err != nil {
+		return err
+	}
+
+	for i := range desired {
+		desired[i].Id = 0
+
+		if desired[i].Status == "" {
+			desired[i].Status = statusActive
+		}
+	}
+
+	create, remove, err := diffRules(data.Data, desired, func(f api.FiltersResponseEntry) api.FiltersResponseEntry {
+		f.Id = 0
+
+		return f
+	})
+	if err != nil {
+		return err
+	}
+
+	// desired rules are created before obsolete ones are deleted,
+	// so a failed sync does not leave the counter without rules
+	for _, f := range create {
+		output.log(actionCreate, "filter", counter, f)
+
+		if output.dryRun {
+			continue
+		}
+
+		if _, err := output.client.Filter.CreateWithContext(ctx, counter, &f); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range remove {
+		output.log(actionDelete, "filter", counter, f)
+
+		if output.dryRun {
+			continue
+		}
+
+		if err := output.client.Filter.DeleteWithContext(ctx, counter, f.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (output *benthosOutput) applyOperations(ctx context.Context, counter int, desired []api.OperationsResponseEntry) error {
+	data, err := output.client.Operation.GetWithContext(ctx, counter)
+	if err != nil {
+		return err
+	}
+
+	for i := range desired {
+		desired[i].Id = 0
+
+		if desired[i].Status == "" {
+			desired[i].Status = statusActive
+		}
+	}
+
+	create, remove, err := diffRules(data.Data, desired, func(o api.OperationsResponseEntry) api.OperationsResponseEntry {
+		o.Id = 0
+
+		return o
+	})
+	if err != nil {
+		return err
+	}
+
+	// desired rules are created before obsolete ones are deleted,
+	// so a failed sync does not leave the counter without rules
+	for _, o := range create {
+		output.log(actionCreate, "operation", counter, o)
+
+		if output.dryRun {
+			continue
+		}
+
+		if _, err := output.client.Operation.CreateWithContext(ctx, counter, &o); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range remove {
+		output.log(actionDelete, "operation", counter, o)
+
+		if output.dryRun {
+			continue
+		}
+
+		if err := output.client.Operation.DeleteWithContext(ctx, counter, o.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (output *benthosOutput) log(action, kind string, counter int, rule any) {
+	b, _ := json.Marshal(rule)
+
+	output.logger.
+		With("action", action, "kind", kind, "counter_id", counter, "rule", string(b), "dry_run", output.dryRun).
+		Info("counter rule is reconciled")
+}
+
+// diffRules returns the desired rules missing from the current ones and the current rules missing from the desired ones.
+// Rules are compared by their JSON representation without identifiers.
+func diffRules[T any](current, desired []T, strip func(T) T) (create, remove []T, err error) {
+	keys := make(map[string]int, len(desired))
+
+	for _, r := range desired {
+		key, err := ruleKey(strip(r))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys[key]++
+	}
+
+	for _, r := range current {
+		key, err := ruleKey(strip(r))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if keys[key] > 0 {
+			keys[key]--
+
+			continue
+		}
+
+		remove = append(remove, r)
+	}
+
+	for _, r := range desired {
+		key, err := ruleKey(strip(r))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if keys[key] > 0 {
+			keys[key]--
+
+			create = append(create, r)
+		}
+	}
+
+	return create, remove, nil
+}
+
+func ruleKey(v any) (string, error) {
+	b, err := json.Marshal(v)
+
+	return string(b), err
+}
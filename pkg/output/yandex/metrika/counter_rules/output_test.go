@@ -0,0 +1,74 @@
+package counter_rules
+
+import (
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRules(t *testing.T) {
+	strip := func(f api.FiltersResponseEntry) api.FiltersResponseEntry {
+		f.Id = 0
+
+		return f
+	}
+
+	filter := func(id uint64, value string) api.FiltersResponseEntry {
+		return api.FiltersResponseEntry{Id: id, Attr: "url", Type: "contain", Value: value, Action: "exclude", Status: statusActive}
+	}
+
+	testCases := []struct {
+		name           string
+		current        []api.FiltersResponseEntry
+		desired        []api.FiltersResponseEntry
+		expectedCreate []api.FiltersResponseEntry
+		expectedRemove []api.FiltersResponseEntry
+	}{
+		{
+			name:    "Equal rules with different identifiers",
+			current: []api.FiltersResponseEntry{filter(1, "a"), filter(2, "b")},
+			desired: []api.FiltersResponseEntry{filter(0, "b"), filter(0, "a")},
+		},
+		{
+			name:           "New rules keep the desired order",
+			current:        []api.FiltersResponseEntry{filter(1, "a")},
+			desired:        []api.FiltersResponseEntry{filter(0, "c"), filter(0, "a"), filter(0, "b")},
+			expectedCreate: []api.FiltersResponseEntry{filter(0, "c"), filter(0, "b")},
+		},
+		{
+			name:           "Obsolete rules keep the current order",
+			current:        []api.FiltersResponseEntry{filter(3, "c"), filter(1, "a"), filter(2, "b")},
+			desired:        []api.FiltersResponseEntry{filter(0, "a")},
+			expectedRemove: []api.FiltersResponseEntry{filter(3, "c"), filter(2, "b")},
+		},
+		{
+			name:           "Changed rule",
+			current:        []api.FiltersResponseEntry{filter(1, "a")},
+			desired:        []api.FiltersResponseEntry{filter(0, "b")},
+			expectedCreate: []api.FiltersResponseEntry{filter(0, "b")},
+			expectedRemove: []api.FiltersResponseEntry{filter(1, "a")},
+		},
+		{
+			name:           "Duplicates",
+			current:        []api.FiltersResponseEntry{filter(1, "a"), filter(2, "a"), filter(3, "a")},
+			desired:        []api.FiltersResponseEntry{filter(0, "a"), filter(0, "a")},
+			expectedRemove: []api.FiltersResponseEntry{filter(3, "a")},
+		},
+		{
+			name:           "Empty desired state",
+			current:        []api.FiltersResponseEntry{filter(1, "a")},
+			desired:        []api.FiltersResponseEntry{},
+			expectedRemove: []api.FiltersResponseEntry{filter(1, "a")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			create, remove, err := diffRules(tc.current, tc.desired, strip)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCreate, create)
+			assert.Equal(t, tc.expectedRemove, remove)
+		})
+	}
+}
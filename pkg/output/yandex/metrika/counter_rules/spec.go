@@ -0,0 +1,43 @@
+package counter_rules
+
+import "github.com/redpanda-data/benthos/v4/public/service"
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that applies Yandex.Metrika API counter filters and operations.").
+		Description(`Each message is a desired state of the counter rules:
+
+`+"```yaml"+`
+filters:
+  - attr: ip
+    type: interval
+    value: 10.0.0.1-10.0.0.255
+    action: exclude
+operations:
+  - action: cut_parameter
+    attr: url
+    value: utm_source
+`+"```"+`
+
+Rules missing from the counter are created first, then counter rules missing from the desired state are deleted.
+A kind missing from the message is left as is, an empty list deletes all rules of the kind.
+The `+"`status`"+` field defaults to `+"`active`"+`.`).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewBoolField("dry_run").
+				Description("Log the rule changes without applying them.").
+				Default(false),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
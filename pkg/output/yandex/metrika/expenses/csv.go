@@ -0,0 +1,92 @@
+package expenses
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// expenseColumns maps the CSV columns to the message fields.
+var expenseColumns = []struct {
+	name  string
+	field string
+}{
+	{name: "Date", field: "date"},
+	{name: "UTMSource", field: "utm_source"},
+	{name: "UTMMedium", field: "utm_medium"},
+	{name: "UTMCampaign", field: "utm_campaign"},
+	{name: "Expenses", field: "expenses"},
+	{name: "Shows", field: "shows"},
+	{name: "Clicks", field: "clicks"},
+	{name: "Currency", field: "currency"},
+}
+
+// encodeCSV converts the messages into the expenses CSV file.
+// It also returns the date ranges of the expenses by the UTM source.
+func encodeCSV(msgs service.MessageBatch) (map[string]*api.ExpenseDeleteQuery, []byte, error) {
+	ranges := map[string]*api.ExpenseDeleteQuery{}
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	header := make([]string, 0, len(expenseColumns))
+	for _, c := range expenseColumns {
+		header = append(header, c.name)
+	}
+
+	if err := w.Write(header); err != nil {
+		return nil, nil, err
+	}
+
+	for _, msg := range msgs {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		row, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil, errors.New("expenses message must be an object")
+		}
+
+		record := make([]string, 0, len(expenseColumns))
+		for _, c := range expenseColumns {
+			record = append(record, upload.FormatValue(row[c.field]))
+		}
+
+		date, source := record[0], record[1]
+
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return nil, nil, fmt.Errorf("expenses date: %w", err)
+		}
+
+		if source == "" {
+			return nil, nil, errors.New("expenses utm_source is required")
+		}
+
+		r, ok := ranges[source]
+		if !ok {
+			r = &api.ExpenseDeleteQuery{Date1: date, Date2: date, UTMSource: source}
+			ranges[source] = r
+		}
+
+		// dates in YYYY-MM-DD format are ordered as strings
+		r.Date1 = min(r.Date1, date)
+		r.Date2 = max(r.Date2, date)
+
+		if err := w.Write(record); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	w.Flush()
+
+	return ranges, buf.Bytes(), w.Error()
+}
@@ -0,0 +1,80 @@
+package expenses
+
+import (
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rows           []any
+		expected       string
+		expectedRanges map[string]*api.ExpenseDeleteQuery
+		expectError    bool
+	}{
+		{
+			name: "Column order and ranges",
+			rows: []any{
+				map[string]any{"currency": "RUB", "clicks": 3.0, "shows": 100.0, "expenses": 12.5, "utm_source": "vk", "date": "2024-01-02"},
+				map[string]any{"date": "2024-01-01", "utm_source": "vk", "utm_medium": "cpc", "utm_campaign": "spring", "expenses": 7.0},
+				map[string]any{"date": "2024-01-03", "utm_source": "google", "expenses": 1.0},
+			},
+			expected: "Date,UTMSource,UTMMedium,UTMCampaign,Expenses,Shows,Clicks,Currency\n" +
+				"2024-01-02,vk,,,12.5,100,3,RUB\n" +
+				"2024-01-01,vk,cpc,spring,7,,,\n" +
+				"2024-01-03,google,,,1,,,\n",
+			expectedRanges: map[string]*api.ExpenseDeleteQuery{
+				"vk":     {Date1: "2024-01-01", Date2: "2024-01-02", UTMSource: "vk"},
+				"google": {Date1: "2024-01-03", Date2: "2024-01-03", UTMSource: "google"},
+			},
+		},
+		{
+			name: "Escaping",
+			rows: []any{
+				map[string]any{"date": "2024-01-01", "utm_source": "vk", "utm_campaign": "sale, \"50%\"", "expenses": 1.0},
+			},
+			expected: "Date,UTMSource,UTMMedium,UTMCampaign,Expenses,Shows,Clicks,Currency\n" +
+				"2024-01-01,vk,,\"sale, \"\"50%\"\"\",1,,,\n",
+			expectedRanges: map[string]*api.ExpenseDeleteQuery{
+				"vk": {Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "vk"},
+			},
+		},
+		{
+			name:        "Date with time",
+			rows:        []any{map[string]any{"date": "2024-01-01T00:00:00Z", "utm_source": "vk", "expenses": 1.0}},
+			expectError: true,
+		},
+		{
+			name:        "Missing source",
+			rows:        []any{map[string]any{"date": "2024-01-01", "expenses": 1.0}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs := make(service.MessageBatch, 0, len(tc.rows))
+
+			for _, row := range tc.rows {
+				msg := service.NewMessage(nil)
+				msg.SetStructured(row)
+				msgs = append(msgs, msg)
+			}
+
+			ranges, data, err := encodeCSV(msgs)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+			assert.Equal(t, tc.expectedRanges, ranges)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package expenses
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_expenses",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type benthosOutput struct {
+	token     string
+	query     *api.ExpenseQuery
+	replace   bool
+	deleted   *deletions
+	writer    *upload.Writer
+	client    *api.Client
+	logger    *service.Logger
+	clientMut sync.Mutex
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client != nil {
+		return nil
+	}
+
+	output.client = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	)
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client == nil {
+		return service.ErrNotConnected
+	}
+
+	return upload.WriteBatch(ctx, output.writer, batch, encodeCSV, output.upload)
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+func (output *benthosOutput) upload(
+	ctx context.Context,
+	counter int,
+	msgs service.MessageBatch,
+	ranges map[string]*api.ExpenseDeleteQuery,
+	data []byte,
+) (upload.Result, error) {
+	if output.replace {
+		if err := output.deleteRanges(ctx, counter, ranges); err != nil {
+			return upload.Result{}, err
+		}
+	}
+
+	resp, err := output.client.Expense.UploadWithContext(ctx, counter, output.query, data)
+	if err != nil {
+		return upload.Result{}, err
+	}
+
+	output.logger.
+		With("counter_id", counter, "upload_id", resp.Uploading.Id, "status", resp.Uploading.Status, "rows", len(msgs)).
+		Info("expenses are uploaded")
+
+	return upload.Result{
+		ID:     strconv.FormatUint(resp.Uploading.Id, 10),
+		Status: resp.Uploading.Status,
+	}, nil
+}
+
+// deleteRanges deletes the expenses of the UTM sources date ranges which are not deleted by the run yet.
+func (output *benthosOutput) deleteRanges(ctx context.Context, counter int, ranges map[string]*api.ExpenseDeleteQuery) error {
+	sources := make([]string, 0, len(ranges))
+	for s := range ranges {
+		sources = append(sources, s)
+	}
+
+	slices.Sort(sources)
+
+	for _, s := range sources {
+		pending, err := output.deleted.pending(counter, ranges[s])
+		if err != nil {
+			return err
+		}
+
+		for _, r := range pending {
+			resp, err := output.client.Expense.DeleteWithContext(ctx, counter, r)
+			if err != nil {
+				return err
+			}
+
+			if err := output.deleted.add(counter, r); err != nil {
+				return err
+			}
+
+			output.logger.
+				With("counter_id", counter, "utm_source", r.UTMSource, "date1", r.Date1, "date2", r.Date2, "status", resp.Uploading.Status).
+				Info("expenses are deleted")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package expenses
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*benthosOutput, error) {
+	output := &benthosOutput{
+		query: &api.ExpenseQuery{},
+		writer: &upload.Writer{
+			Uploads: upload.NewTracker(upload.TrackerTTL),
+			Name:    "expenses",
+			Logger:  mgr.Logger(),
+		},
+		logger: mgr.Logger(),
+	}
+
+	var err error
+
+	if conf.Contains("token") {
+		output.token, err = conf.FieldString("token")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.writer.Counter, err = conf.FieldInterpolatedString("counter_id")
+	if err != nil {
+		return nil, err
+	}
+
+	output.writer.SyncResponse, err = conf.FieldBool("sync_response")
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains("provider") {
+		output.query.Provider, err = conf.FieldString("provider")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("comment") {
+		output.query.Comment, err = conf.FieldString("comment")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.replace, err = conf.FieldBool("replace")
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := conf.FieldDuration("replace_window")
+	if err != nil {
+		return nil, err
+	}
+
+	output.deleted = newDeletions(window)
+
+	return output, nil
+}
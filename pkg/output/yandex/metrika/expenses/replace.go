@@ -0,0 +1,106 @@
+package expenses
+
+import (
+	"time"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+)
+
+// deletionKey identifies the expenses of a UTM source of a counter.
+type deletionKey struct {
+	counter int
+	source  string
+}
+
+// deletions remembers the dates which are deleted during the run, so the expenses uploaded by
+// the previous batches of the same dates are not deleted by the next batches. A run lasts for the
+// window after a deletion of the date, so a correction sent later deletes the date again.
+type deletions struct {
+	window time.Duration
+	now    func() time.Time
+	dates  map[deletionKey]map[string]time.Time
+}
+
+func newDeletions(window time.Duration) *deletions {
+	return &deletions{
+		window: window,
+		now:    time.Now,
+		dates:  map[deletionKey]map[string]time.Time{},
+	}
+}
+
+// pending splits the range into the ranges of the dates which are not deleted yet.
+func (d *deletions) pending(counter int, r *api.ExpenseDeleteQuery) ([]*api.ExpenseDeleteQuery, error) {
+	deleted := d.dates[deletionKey{counter: counter, source: r.UTMSource}]
+	now := d.now()
+
+	var (
+		ranges  []*api.ExpenseDeleteQuery
+		current *api.ExpenseDeleteQuery
+	)
+
+	err := walkDates(r, func(date string) {
+		if expires, ok := deleted[date]; ok && now.Before(expires) {
+			current = nil
+
+			return
+		}
+
+		if current == nil {
+			current = &api.ExpenseDeleteQuery{Date1: date, UTMSource: r.UTMSource}
+			ranges = append(ranges, current)
+		}
+
+		current.Date2 = date
+	})
+
+	return ranges, err
+}
+
+// add marks the dates of the range as deleted for the window and drops the expired dates.
+func (d *deletions) add(counter int, r *api.ExpenseDeleteQuery) error {
+	now := d.now()
+
+	for key, deleted := range d.dates {
+		for date, expires := range deleted {
+			if !now.Before(expires) {
+				delete(deleted, date)
+			}
+		}
+
+		if len(deleted) == 0 {
+			delete(d.dates, key)
+		}
+	}
+
+	key := deletionKey{counter: counter, source: r.UTMSource}
+
+	deleted, ok := d.dates[key]
+	if !ok {
+		deleted = map[string]time.Time{}
+		d.dates[key] = deleted
+	}
+
+	return walkDates(r, func(date string) {
+		deleted[date] = now.Add(d.window)
+	})
+}
+
+// walkDates calls the function for each date of the range.
+func walkDates(r *api.ExpenseDeleteQuery, fn func(date string)) error {
+	date1, err := time.Parse(time.DateOnly, r.Date1)
+	if err != nil {
+		return err
+	}
+
+	date2, err := time.Parse(time.DateOnly, r.Date2)
+	if err != nil {
+		return err
+	}
+
+	for t := date1; !t.After(date2); t = t.AddDate(0, 0, 1) {
+		fn(t.Format(time.DateOnly))
+	}
+
+	return nil
+}
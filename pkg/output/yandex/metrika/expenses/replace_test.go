@@ -0,0 +1,148 @@
+package expenses
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletions_Pending(t *testing.T) {
+	d := newDeletions(time.Hour)
+
+	require.NoError(t, d.add(1, &api.ExpenseDeleteQuery{Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "vk"}))
+	require.NoError(t, d.add(1, &api.ExpenseDeleteQuery{Date1: "2024-01-03", Date2: "2024-01-03", UTMSource: "vk"}))
+
+	testCases := []struct {
+		name     string
+		counter  int
+		r        *api.ExpenseDeleteQuery
+		expected []*api.ExpenseDeleteQuery
+	}{
+		{
+			name:    "Gaps",
+			counter: 1,
+			r:       &api.ExpenseDeleteQuery{Date1: "2024-01-01", Date2: "2024-01-05", UTMSource: "vk"},
+			expected: []*api.ExpenseDeleteQuery{
+				{Date1: "2024-01-02", Date2: "2024-01-02", UTMSource: "vk"},
+				{Date1: "2024-01-04", Date2: "2024-01-05", UTMSource: "vk"},
+			},
+		},
+		{
+			name:    "Deleted range",
+			counter: 1,
+			r:       &api.ExpenseDeleteQuery{Date1: "2024-01-03", Date2: "2024-01-03", UTMSource: "vk"},
+		},
+		{
+			name:     "Other source",
+			counter:  1,
+			r:        &api.ExpenseDeleteQuery{Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "google"},
+			expected: []*api.ExpenseDeleteQuery{{Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "google"}},
+		},
+		{
+			name:     "Other counter",
+			counter:  2,
+			r:        &api.ExpenseDeleteQuery{Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "vk"},
+			expected: []*api.ExpenseDeleteQuery{{Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "vk"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := d.pending(tc.counter, tc.r)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestDeletions_ConsecutiveBatches(t *testing.T) {
+	d := newDeletions(time.Hour)
+
+	// deleteBatch returns the ranges deleted before the upload of the batch rows.
+	deleteBatch := func(rows ...map[string]any) []*api.ExpenseDeleteQuery {
+		msgs := make(service.MessageBatch, 0, len(rows))
+
+		for _, row := range rows {
+			msg := service.NewMessage(nil)
+			msg.SetStructured(row)
+			msgs = append(msgs, msg)
+		}
+
+		ranges, _, err := encodeCSV(msgs)
+		require.NoError(t, err)
+
+		var deleted []*api.ExpenseDeleteQuery
+
+		for _, source := range []string{"google", "vk"} {
+			r, ok := ranges[source]
+			if !ok {
+				continue
+			}
+
+			pending, err := d.pending(1, r)
+			require.NoError(t, err)
+
+			for _, p := range pending {
+				require.NoError(t, d.add(1, p))
+			}
+
+			deleted = append(deleted, pending...)
+		}
+
+		return deleted
+	}
+
+	deleted := deleteBatch(
+		map[string]any{"date": "2024-01-01", "utm_source": "vk", "utm_campaign": "a", "expenses": 10.0},
+		map[string]any{"date": "2024-01-02", "utm_source": "vk", "utm_campaign": "a", "expenses": 20.0},
+	)
+	assert.Equal(t, []*api.ExpenseDeleteQuery{{Date1: "2024-01-01", Date2: "2024-01-02", UTMSource: "vk"}}, deleted)
+
+	// the next batch of the same dates does not delete the expenses of the previous batch
+	deleted = deleteBatch(
+		map[string]any{"date": "2024-01-02", "utm_source": "vk", "utm_campaign": "b", "expenses": 5.0},
+		map[string]any{"date": "2024-01-03", "utm_source": "vk", "utm_campaign": "b", "expenses": 7.0},
+		map[string]any{"date": "2024-01-02", "utm_source": "google", "utm_campaign": "c", "expenses": 3.0},
+	)
+	assert.Equal(t, []*api.ExpenseDeleteQuery{
+		{Date1: "2024-01-02", Date2: "2024-01-02", UTMSource: "google"},
+		{Date1: "2024-01-03", Date2: "2024-01-03", UTMSource: "vk"},
+	}, deleted)
+}
+
+func TestDeletions_Correction(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	d := newDeletions(time.Hour)
+	d.now = func() time.Time { return now }
+
+	r := &api.ExpenseDeleteQuery{Date1: "2024-01-01", Date2: "2024-01-01", UTMSource: "vk"}
+
+	pending, err := d.pending(1, r)
+	require.NoError(t, err)
+	assert.Equal(t, []*api.ExpenseDeleteQuery{r}, pending)
+	require.NoError(t, d.add(1, r))
+
+	// a batch of the same run does not delete the date again
+	now = now.Add(30 * time.Minute)
+
+	pending, err = d.pending(1, r)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// a second correction of the date after the run replaces the expenses again
+	now = now.Add(time.Hour)
+
+	pending, err = d.pending(1, r)
+	require.NoError(t, err)
+	assert.Equal(t, []*api.ExpenseDeleteQuery{r}, pending)
+	require.NoError(t, d.add(1, r))
+
+	pending, err = d.pending(1, r)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
@@ -0,0 +1,47 @@
+package expenses
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that uploads advertising expenses to Yandex.Metrika API.").
+		Description(upload.Description(`Each batch of messages is uploaded as a CSV file per counter. A message is an object with the fields:
+
+- `+"`date`"+`: the expenses date in YYYY-MM-DD format;
+- `+"`utm_source`, `utm_medium`, `utm_campaign`"+`: the UTM tags of the expenses, the source is required;
+- `+"`expenses`"+`: the expenses amount;
+- `+"`shows` and `clicks`"+`: optional numbers of the ad shows and clicks;
+- `+"`currency`"+`: an optional ISO 4217 currency code.`)).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewStringField("provider").
+				Description("Name of the expenses source.").
+				Example("vk").
+				Optional(),
+			service.NewStringField("comment").
+				Description("Comment of the uploads.").
+				Optional(),
+			service.NewBoolField("replace").
+				Description("Delete the uploaded expenses of the batch date range and UTM sources before the upload. Allows to re-upload the expenses without duplicates. The dates of a UTM source are deleted once per run, so the next batches of the same dates are added to the previous ones. A run must contain all expenses of the replaced sources and dates, since the expenses which are not sent by the run are lost.").
+				Default(false),
+			service.NewDurationField("replace_window").
+				Description("Duration of a run of the `replace` mode. The batches of a deleted date within the window are added to the previous ones, a correction of the date sent after the window replaces them again.").
+				Default("1h"),
+			upload.SyncResponseField(),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
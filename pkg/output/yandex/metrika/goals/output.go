@@ -0,0 +1,301 @@
+package goals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_goals",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Goal changes of the reconciliation.
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+type benthosOutput struct {
+	token       string
+	counter     *service.InterpolatedString
+	deleteCheck *bloblang.Executor
+	dryRun      bool
+	goals       goalService
+	logger      *service.Logger
+	clientMut   sync.Mutex
+}
+
+// goalService is the goals API used by the output.
+type goalService interface {
+	GetWithContext(ctx context.Context, counter int) (*api.GoalsResponse, error)
+	CreateWithContext(ctx context.Context, counter int, goal *api.GoalsResponseEntry) (*api.GoalResponse, error)
+	UpdateWithContext(ctx context.Context, counter int, goal *api.GoalsResponseEntry) (*api.GoalResponse, error)
+	DeleteWithContext(ctx context.Context, counter int, goal uint64) error
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.goals != nil {
+		return nil
+	}
+
+	output.goals = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	).Goal
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.goals == nil {
+		return service.ErrNotConnected
+	}
+
+	// current goals of the batch counters by the goal name
+	current := map[int]map[string]api.GoalsResponseEntry{}
+
+	for i, msg := range batch {
+		raw, err := batch.TryInterpolatedString(i, output.counter)
+		if err != nil {
+			return fmt.Errorf("counter_id interpolation: %w", err)
+		}
+
+		counter, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid counter_id %q: %w", raw, err)
+		}
+
+		goals, ok := current[counter]
+		if !ok {
+			goals, err = output.fetch(ctx, counter)
+			if err != nil {
+				return err
+			}
+
+			current[counter] = goals
+		}
+
+		b, err := msg.AsBytes()
+		if err != nil {
+			return err
+		}
+
+		var desired api.GoalsResponseEntry
+
+		if err := json.Unmarshal(b, &desired); err != nil {
+			return err
+		}
+
+		if desired.Name == "" {
+			return errors.New("goal name is required")
+		}
+
+		deleted, err := output.isDeleted(batch, i)
+		if err != nil {
+			return err
+		}
+
+		goal, exists := goals[desired.Name]
+
+		switch {
+		case deleted && exists:
+			if err := output.apply(ctx, actionDelete, counter, &goal, nil); err != nil {
+				return err
+			}
+
+			delete(goals, desired.Name)
+		case deleted:
+			continue
+		case !exists:
+			if err := output.apply(ctx, actionCreate, counter, nil, &desired); err != nil {
+				return err
+			}
+
+			goals[desired.Name] = desired
+		default:
+			changed, err := goalChanged(goal, desired)
+			if err != nil {
+				return err
+			}
+
+			if !changed {
+				continue
+			}
+
+			desired.Id = goal.Id
+
+			if err := output.apply(ctx, actionUpdate, counter, &goal, &desired); err != nil {
+				return err
+			}
+
+			goals[desired.Name] = desired
+		}
+	}
+
+	return nil
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+// fetch returns the counter goals by the goal name.
+func (output *benthosOutput) fetch(ctx context.Context, counter int) (map[string]api.GoalsResponseEntry, error) {
+	data, err := output.goals.GetWithContext(ctx, counter)
+	if err != nil {
+		return nil, err
+	}
+
+	goals := make(map[string]api.GoalsResponseEntry, len(data.Data))
+
+	for _, g := range data.Data {
+		if _, ok := goals[g.Name]; !ok {
+			goals[g.Name] = g
+		}
+	}
+
+	return goals, nil
+}
+
+func (output *benthosOutput) isDeleted(batch service.MessageBatch, i int) (bool, error) {
+	if output.deleteCheck == nil {
+		return false, nil
+	}
+
+	res, err := batch.BloblangQuery(i, output.deleteCheck)
+	if err != nil {
+		return false, fmt.Errorf("delete_check: %w", err)
+	}
+
+	if res == nil {
+		return false, nil
+	}
+
+	v, err := res.AsStructured()
+	if err != nil {
+		return false, fmt.Errorf("delete_check: %w", err)
+	}
+
+	deleted, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("delete_check must return a boolean, got %T", v)
+	}
+
+	return deleted, nil
+}
+
+// apply logs the goal change and sends it to the API unless the dry run mode is enabled.
+func (output *benthosOutput) apply(ctx context.Context, action string, counter int, current, desired *api.GoalsResponseEntry) error {
+	logger := output.logger.With("action", action, "counter_id", counter, "dry_run", output.dryRun)
+
+	if current != nil {
+		b, _ := json.Marshal(current)
+		logger = logger.With("goal_id", current.Id, "current", string(b))
+	}
+
+	if desired != nil {
+		b, _ := json.Marshal(desired)
+		logger = logger.With("desired", string(b))
+	}
+
+	logger.Info("goal is reconciled")
+
+	if output.dryRun {
+		return nil
+	}
+
+	var err error
+
+	switch action {
+	case actionCreate:
+		var resp *api.GoalResponse
+
+		resp, err = output.goals.CreateWithContext(ctx, counter, desired)
+		if err == nil {
+			desired.Id = resp.Data.Id
+		}
+	case actionUpdate:
+		_, err = output.goals.UpdateWithContext(ctx, counter, desired)
+	case actionDelete:
+		err = output.goals.DeleteWithContext(ctx, counter, current.Id)
+	}
+
+	return err
+}
+
+// goalChanged reports whether the desired goal differs from the current one.
+// Identifiers and fields managed by Metrika are ignored.
+func goalChanged(current, desired api.GoalsResponseEntry) (bool, error) {
+	a, err := json.Marshal(normalizeGoal(current))
+	if err != nil {
+		return false, err
+	}
+
+	b, err := json.Marshal(normalizeGoal(desired))
+	if err != nil {
+		return false, err
+	}
+
+	return string(a) != string(b), nil
+}
+
+func normalizeGoal(g api.GoalsResponseEntry) api.GoalsResponseEntry {
+	g.Id = 0
+	g.PrevID = 0
+	g.Source = ""
+	g.IsFavorite = 0
+
+	if len(g.Steps) > 0 {
+		steps := make([]api.GoalsResponseEntry, len(g.Steps))
+
+		for i, s := range g.Steps {
+			steps[i] = normalizeGoal(s)
+		}
+
+		g.Steps = steps
+	}
+
+	return g
+}
@@ -0,0 +1,141 @@
+package goals
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGoals is a goalService recording the goal changes.
+type fakeGoals struct {
+	goals   []api.GoalsResponseEntry
+	changes []string
+}
+
+func (f *fakeGoals) GetWithContext(ctx context.Context, counter int) (*api.GoalsResponse, error) {
+	return &api.GoalsResponse{Data: f.goals}, nil
+}
+
+func (f *fakeGoals) CreateWithContext(ctx context.Context, counter int, goal *api.GoalsResponseEntry) (*api.GoalResponse, error) {
+	f.changes = append(f.changes, fmt.Sprintf("create %d %s", counter, goal.Name))
+
+	created := *goal
+	created.Id = 200
+
+	return &api.GoalResponse{Data: created}, nil
+}
+
+func (f *fakeGoals) UpdateWithContext(ctx context.Context, counter int, goal *api.GoalsResponseEntry) (*api.GoalResponse, error) {
+	f.changes = append(f.changes, fmt.Sprintf("update %d %s %d", counter, goal.Name, goal.Id))
+
+	return &api.GoalResponse{Data: *goal}, nil
+}
+
+func (f *fakeGoals) DeleteWithContext(ctx context.Context, counter int, goal uint64) error {
+	f.changes = append(f.changes, fmt.Sprintf("delete %d %d", counter, goal))
+
+	return nil
+}
+
+func TestOutput_WriteBatch(t *testing.T) {
+	current := []api.GoalsResponseEntry{
+		{Id: 1, Name: "Cart", Type: "url", Source: "user", IsFavorite: 1, Conditions: []map[string]string{{"type": "contain", "url": "/cart"}}},
+		{Id: 2, Name: "Order", Type: "url", Conditions: []map[string]string{{"type": "contain", "url": "/order"}}},
+		{Id: 3, Name: "Call", Type: "phone"},
+	}
+
+	testCases := []struct {
+		name     string
+		messages []string
+		dryRun   bool
+		expected []string
+	}{
+		{
+			name: "create",
+			messages: []string{
+				`{"name": "Signup", "type": "url", "conditions": [{"type": "contain", "url": "/signup"}]}`,
+			},
+			expected: []string{"create 1 Signup"},
+		},
+		{
+			name: "update",
+			messages: []string{
+				`{"name": "Order", "type": "url", "conditions": [{"type": "contain", "url": "/checkout"}]}`,
+			},
+			expected: []string{"update 1 Order 2"},
+		},
+		{
+			name: "skip equal",
+			messages: []string{
+				`{"id": 10, "name": "Cart", "type": "url", "goal_source": "auto", "conditions": [{"type": "contain", "url": "/cart"}]}`,
+			},
+			expected: nil,
+		},
+		{
+			name: "delete",
+			messages: []string{
+				`{"name": "Call", "deleted": true}`,
+				`{"name": "Missing", "deleted": true}`,
+			},
+			expected: []string{"delete 1 3"},
+		},
+		{
+			name: "create once",
+			messages: []string{
+				`{"name": "Signup", "type": "url"}`,
+				`{"name": "Signup", "type": "url"}`,
+			},
+			expected: []string{"create 1 Signup"},
+		},
+		{
+			name: "dry run",
+			messages: []string{
+				`{"name": "Signup", "type": "url"}`,
+				`{"name": "Order", "type": "url"}`,
+				`{"name": "Call", "deleted": true}`,
+			},
+			dryRun:   true,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter, err := service.NewInterpolatedString("1")
+			require.NoError(t, err)
+
+			deleteCheck, err := bloblang.Parse(`this.deleted.or(false)`)
+			require.NoError(t, err)
+
+			goals := &fakeGoals{goals: current}
+			output := &benthosOutput{
+				counter:     counter,
+				deleteCheck: deleteCheck,
+				dryRun:      tc.dryRun,
+				goals:       goals,
+				logger:      service.MockResources().Logger(),
+			}
+
+			batch := make(service.MessageBatch, len(tc.messages))
+			for i, m := range tc.messages {
+				batch[i] = service.NewMessage([]byte(m))
+			}
+
+			require.NoError(t, output.WriteBatch(context.Background(), batch))
+			assert.Equal(t, tc.expected, goals.changes)
+		})
+	}
+}
+
+func TestOutput_WriteBatchNotConnected(t *testing.T) {
+	output := &benthosOutput{}
+
+	err := output.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte(`{}`))})
+	assert.ErrorIs(t, err, service.ErrNotConnected)
+}
@@ -0,0 +1,39 @@
+package goals
+
+import (
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*benthosOutput, error) {
+	output := &benthosOutput{
+		logger: mgr.Logger(),
+	}
+
+	var err error
+
+	if conf.Contains("token") {
+		output.token, err = conf.FieldString("token")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.counter, err = conf.FieldInterpolatedString("counter_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains("delete_check") {
+		output.deleteCheck, err = conf.FieldBloblang("delete_check")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.dryRun, err = conf.FieldBool("dry_run")
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
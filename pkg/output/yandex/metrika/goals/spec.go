@@ -0,0 +1,33 @@
+package goals
+
+import "github.com/redpanda-data/benthos/v4/public/service"
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that reconciles Yandex.Metrika API goals.").
+		Description(`Each message is a desired goal in the format of the `+"`yandex_metrika_goals`"+` input keyed by the goal name.
+A goal missing from the counter is created, a goal that differs from the desired one is updated, an equal goal is skipped.
+Composite goals are compared with their steps and conditions.`).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewBloblangField("delete_check").
+				Description("A Bloblang query that should return `true` for goals to delete.").
+				Example(`this.deleted.or(false)`).
+				Optional(),
+			service.NewBoolField("dry_run").
+				Description("Log the goal changes without applying them.").
+				Default(false),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
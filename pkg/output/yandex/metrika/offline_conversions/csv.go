@@ -0,0 +1,72 @@
+package offline_conversions
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// clientIDTypes are the supported visitor identifier types.
+var clientIDTypes = []string{"CLIENT_ID", "USER_ID", "YCLID"}
+
+// idColumns maps the visitor identifier types to the CSV column names.
+var idColumns = map[string]string{
+	"CLIENT_ID": "ClientId",
+	"USER_ID":   "UserId",
+	"YCLID":     "Yclid",
+}
+
+// encodeCSV converts the messages into the offline conversions CSV file.
+func encodeCSV(clientIDType string, msgs service.MessageBatch) ([]byte, error) {
+	idKey := strings.ToLower(clientIDType)
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{idColumns[clientIDType], "Target", "DateTime", "Price", "Currency"}); err != nil {
+		return nil, err
+	}
+
+	for _, msg := range msgs {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return nil, err
+		}
+
+		row, ok := v.(map[string]any)
+		if !ok {
+			return nil, errors.New("offline conversion message must be an object")
+		}
+
+		id := upload.FormatValue(row[idKey])
+		if id == "" {
+			return nil, fmt.Errorf("offline conversion %s is required", idKey)
+		}
+
+		target := upload.FormatValue(row["target"])
+		if target == "" {
+			return nil, errors.New("offline conversion target is required")
+		}
+
+		ts, err := upload.FormatTime(row["date_time"])
+		if err != nil {
+			return nil, fmt.Errorf("offline conversion date_time: %w", err)
+		}
+
+		record := []string{id, target, ts, upload.FormatValue(row["price"]), upload.FormatValue(row["currency"])}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,90 @@
+package offline_conversions
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	testCases := []struct {
+		name         string
+		clientIDType string
+		rows         []any
+		expected     string
+		expectError  bool
+	}{
+		{
+			name:         "Column order",
+			clientIDType: "CLIENT_ID",
+			rows: []any{
+				map[string]any{"currency": "RUB", "price": 100.5, "date_time": 1704164645.0, "target": "order", "client_id": "123"},
+				map[string]any{"client_id": 456.0, "target": "call", "date_time": "1704164646"},
+			},
+			expected: "ClientId,Target,DateTime,Price,Currency\n123,order,1704164645,100.5,RUB\n456,call,1704164646,,\n",
+		},
+		{
+			name:         "Escaping",
+			clientIDType: "USER_ID",
+			rows: []any{
+				map[string]any{"user_id": "a,b", "target": "say \"hi\"", "date_time": "1704164645"},
+			},
+			expected: "UserId,Target,DateTime,Price,Currency\n\"a,b\",\"say \"\"hi\"\"\",1704164645,,\n",
+		},
+		{
+			name:         "RFC 3339 time",
+			clientIDType: "YCLID",
+			rows: []any{
+				map[string]any{"yclid": "987", "target": "order", "date_time": "2024-01-02T06:04:05+03:00"},
+			},
+			expected: "Yclid,Target,DateTime,Price,Currency\n987,order,1704164645,,\n",
+		},
+		{
+			name:         "Invalid time",
+			clientIDType: "CLIENT_ID",
+			rows:         []any{map[string]any{"client_id": "123", "target": "order", "date_time": "2024-01-02"}},
+			expectError:  true,
+		},
+		{
+			name:         "Missing identifier of the type",
+			clientIDType: "USER_ID",
+			rows:         []any{map[string]any{"client_id": "123", "target": "order", "date_time": "1704164645"}},
+			expectError:  true,
+		},
+		{
+			name:         "Missing target",
+			clientIDType: "CLIENT_ID",
+			rows:         []any{map[string]any{"client_id": "123", "date_time": "1704164645"}},
+			expectError:  true,
+		},
+		{
+			name:         "Not an object",
+			clientIDType: "CLIENT_ID",
+			rows:         []any{[]any{"123"}},
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs := make(service.MessageBatch, 0, len(tc.rows))
+
+			for _, row := range tc.rows {
+				msg := service.NewMessage(nil)
+				msg.SetStructured(row)
+				msgs = append(msgs, msg)
+			}
+
+			data, err := encodeCSV(tc.clientIDType, msgs)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package offline_conversions
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*benthosOutput, error) {
+	output := &benthosOutput{
+		query: &api.OfflineConversionQuery{},
+		writer: &upload.Writer{
+			Uploads: upload.NewTracker(upload.TrackerTTL),
+			Name:    "offline conversions",
+			Logger:  mgr.Logger(),
+		},
+		logger: mgr.Logger(),
+	}
+
+	var err error
+
+	if conf.Contains("token") {
+		output.token, err = conf.FieldString("token")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.writer.Counter, err = conf.FieldInterpolatedString("counter_id")
+	if err != nil {
+		return nil, err
+	}
+
+	output.writer.SyncResponse, err = conf.FieldBool("sync_response")
+	if err != nil {
+		return nil, err
+	}
+
+	output.query.ClientIDType, err = conf.FieldString("client_id_type")
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains("comment") {
+		output.query.Comment, err = conf.FieldString("comment")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.wait, err = conf.FieldBool("wait")
+	if err != nil {
+		return nil, err
+	}
+
+	output.waitInterval, err = conf.FieldDuration("wait_interval")
+	if err != nil {
+		return nil, err
+	}
+
+	output.waitTimeout, err = conf.FieldDuration("wait_timeout")
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,49 @@
+package offline_conversions
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that uploads offline conversions to Yandex.Metrika API.").
+		Description(upload.Description(`Each batch of messages is uploaded as a CSV file per counter. A message is an object with the fields:
+
+- `+"`client_id`, `user_id` or `yclid`"+`: the visitor identifier of the `+"`client_id_type`"+`;
+- `+"`target`"+`: the goal identifier;
+- `+"`date_time`"+`: the conversion time as a Unix timestamp or an RFC 3339 string;
+- `+"`price`"+`: an optional conversion value;
+- `+"`currency`"+`: an optional ISO 4217 currency code.`)).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewStringEnumField("client_id_type", clientIDTypes...).
+				Description("Type of the visitor identifier.").
+				Default("CLIENT_ID"),
+			service.NewStringField("comment").
+				Description("Comment of the uploads.").
+				Optional(),
+			service.NewBoolField("wait").
+				Description("Wait for the upload processing. A failed processing is logged as an error and the messages are acknowledged, since a retry of the same file does not fix it.").
+				Default(false),
+			service.NewDurationField("wait_interval").
+				Description("Interval between the upload status checks.").
+				Default("30s"),
+			service.NewDurationField("wait_timeout").
+				Description("Maximum time to wait for the upload processing. The batch is acknowledged after the timeout.").
+				Default("30m"),
+			upload.SyncResponseField(),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
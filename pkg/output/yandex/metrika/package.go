@@ -0,0 +1,12 @@
+package metrika
+
+import (
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/calls"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/cdp"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/counter_rules"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/expenses"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/goals"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/offline_conversions"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/segments"
+	_ "github.com/artemklevtsov/redpanda-connect-plugins/pkg/output/yandex/metrika/user_params"
+)
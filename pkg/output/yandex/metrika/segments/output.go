@@ -0,0 +1,247 @@
+package segments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/filter"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_segments",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Segment changes of the sync.
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+)
+
+type benthosOutput struct {
+	token     string
+	counter   *service.InterpolatedString
+	dryRun    bool
+	client    *api.Client
+	logger    *service.Logger
+	clientMut sync.Mutex
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client != nil {
+		return nil
+	}
+
+	output.client = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	)
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client == nil {
+		return service.ErrNotConnected
+	}
+
+	// current segments of the batch counters by the segment name
+	current := map[int]map[string]api.SegmentsResponseEntry{}
+
+	for i, msg := range batch {
+		raw, err := batch.TryInterpolatedString(i, output.counter)
+		if err != nil {
+			return fmt.Errorf("counter_id interpolation: %w", err)
+		}
+
+		counter, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid counter_id %q: %w", raw, err)
+		}
+
+		segments, ok := current[counter]
+		if !ok {
+			segments, err = output.fetch(ctx, counter)
+			if err != nil {
+				return err
+			}
+
+			current[counter] = segments
+		}
+
+		desired, err := segmentFromMessage(msg)
+		if err != nil {
+			return err
+		}
+
+		segment, exists := segments[desired.Name]
+
+		switch {
+		case !exists:
+			if err := output.apply(ctx, actionCreate, counter, nil, desired); err != nil {
+				return err
+			}
+		case segmentChanged(&segment, desired):
+			desired.Id = segment.Id
+
+			if err := output.apply(ctx, actionUpdate, counter, &segment, desired); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		segments[desired.Name] = *desired
+	}
+
+	return nil
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+// fetch returns the counter segments by the segment name.
+func (output *benthosOutput) fetch(ctx context.Context, counter int) (map[string]api.SegmentsResponseEntry, error) {
+	data, err := output.client.Segment.GetWithContext(ctx, counter)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make(map[string]api.SegmentsResponseEntry, len(data.Data))
+
+	for _, s := range data.Data {
+		if _, ok := segments[s.Name]; !ok {
+			segments[s.Name] = s
+		}
+	}
+
+	return segments, nil
+}
+
+// apply logs the segment change and sends it to the API unless the dry run mode is enabled.
+func (output *benthosOutput) apply(ctx context.Context, action string, counter int, current, desired *api.SegmentsResponseEntry) error {
+	logger := output.logger.With("action", action, "counter_id", counter, "name", desired.Name, "expression", desired.Expression, "dry_run", output.dryRun)
+
+	if current != nil {
+		logger = logger.With("segment_id", current.Id, "current_expression", current.Expression)
+	}
+
+	logger.Info("segment is synced")
+
+	if output.dryRun {
+		return nil
+	}
+
+	switch action {
+	case actionCreate:
+		resp, err := output.client.Segment.CreateWithContext(ctx, counter, desired)
+		if err != nil {
+			return err
+		}
+
+		desired.Id = resp.Data.Id
+	case actionUpdate:
+		if _, err := output.client.Segment.UpdateWithContext(ctx, counter, desired); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// segmentChanged reports whether the segment fields managed by the output differ.
+func segmentChanged(current, desired *api.SegmentsResponseEntry) bool {
+	return current.Expression != desired.Expression ||
+		current.IsRetarget != desired.IsRetarget ||
+		current.Description != desired.Description
+}
+
+// segmentFromMessage parses a segment message. A structured expression is compiled into the filter syntax.
+// The message is not modified.
+func segmentFromMessage(msg *service.Message) (*api.SegmentsResponseEntry, error) {
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New("segment message must be an object")
+	}
+
+	// the structured message value is shared, so the compiled expression is set to a copy
+	obj := maps.Clone(fields)
+
+	if expr, ok := obj["expression"].(map[string]any); ok {
+		compiled, err := filter.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("segment expression: %w", err)
+		}
+
+		obj["expression"] = compiled
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var segment api.SegmentsResponseEntry
+
+	if err := json.Unmarshal(b, &segment); err != nil {
+		return nil, err
+	}
+
+	if segment.Name == "" {
+		return nil, errors.New("segment name is required")
+	}
+
+	if segment.Expression == "" {
+		return nil, errors.New("segment expression is required")
+	}
+
+	segment.Id = 0
+	segment.CounterID = 0
+
+	return &segment, nil
+}
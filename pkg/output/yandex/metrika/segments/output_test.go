@@ -0,0 +1,137 @@
+package segments
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentFromMessage(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       any
+		expected    *api.SegmentsResponseEntry
+		expectError bool
+	}{
+		{
+			name: "Filter string",
+			value: map[string]any{
+				"segment_id":     12.0,
+				"counter_id":     44147844.0,
+				"name":           "Direct",
+				"expression":     "ym:s:lastTrafficSource=='direct'",
+				"is_retargeting": true,
+				"description":    "Direct traffic",
+			},
+			expected: &api.SegmentsResponseEntry{
+				Name:        "Direct",
+				Expression:  "ym:s:lastTrafficSource=='direct'",
+				IsRetarget:  true,
+				Description: "Direct traffic",
+			},
+		},
+		{
+			name: "Structured expression",
+			value: map[string]any{
+				"name":       "Direct",
+				"expression": map[string]any{"field": "ym:s:lastTrafficSource", "op": "eq", "value": "direct"},
+			},
+			expected: &api.SegmentsResponseEntry{
+				Name:       "Direct",
+				Expression: "ym:s:lastTrafficSource=='direct'",
+			},
+		},
+		{
+			name:        "Invalid structured expression",
+			value:       map[string]any{"name": "Direct", "expression": map[string]any{"op": "eq"}},
+			expectError: true,
+		},
+		{
+			name:        "Missing name",
+			value:       map[string]any{"expression": "ym:s:isRobot=='No'"},
+			expectError: true,
+		},
+		{
+			name:        "Missing expression",
+			value:       map[string]any{"name": "Direct"},
+			expectError: true,
+		},
+		{
+			name:        "Not an object",
+			value:       []any{"Direct"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := service.NewMessage(nil)
+			msg.SetStructured(tc.value)
+
+			before, err := json.Marshal(tc.value)
+			assert.NoError(t, err)
+
+			segment, err := segmentFromMessage(msg)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, segment)
+			}
+
+			// the message value is not modified
+			v, err := msg.AsStructured()
+			assert.NoError(t, err)
+
+			after, err := json.Marshal(v)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(before), string(after))
+		})
+	}
+}
+
+func TestSegmentChanged(t *testing.T) {
+	current := &api.SegmentsResponseEntry{
+		Id:          12,
+		Name:        "Direct",
+		Expression:  "ym:s:lastTrafficSource=='direct'",
+		Source:      "api",
+		Description: "Direct traffic",
+	}
+
+	testCases := []struct {
+		name     string
+		desired  api.SegmentsResponseEntry
+		expected bool
+	}{
+		{
+			name:     "Equal",
+			desired:  api.SegmentsResponseEntry{Name: "Direct", Expression: "ym:s:lastTrafficSource=='direct'", Description: "Direct traffic"},
+			expected: false,
+		},
+		{
+			name:     "Expression",
+			desired:  api.SegmentsResponseEntry{Name: "Direct", Expression: "ym:s:lastTrafficSource=='ad'", Description: "Direct traffic"},
+			expected: true,
+		},
+		{
+			name:     "Retargeting",
+			desired:  api.SegmentsResponseEntry{Name: "Direct", Expression: "ym:s:lastTrafficSource=='direct'", IsRetarget: true, Description: "Direct traffic"},
+			expected: true,
+		},
+		{
+			name:     "Description",
+			desired:  api.SegmentsResponseEntry{Name: "Direct", Expression: "ym:s:lastTrafficSource=='direct'"},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, segmentChanged(current, &tc.desired))
+		})
+	}
+}
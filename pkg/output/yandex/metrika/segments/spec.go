@@ -0,0 +1,41 @@
+package segments
+
+import "github.com/redpanda-data/benthos/v4/public/service"
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that syncs Yandex.Metrika API segments.").
+		Description(`Each message is a segment in the format of the `+"`yandex_metrika_segments`"+` input keyed by the segment name.
+A segment missing from the counter is created, a segment with a different expression, retargeting flag or description is updated, an equal segment is skipped.
+The `+"`expression`"+` may be a filter string or a structured filter object:
+
+`+"```yaml"+`
+name: Mobile users from Russia
+expression:
+  and:
+    - field: ym:s:isMobile
+      op: eq
+      value: "Yes"
+    - field: ym:s:regionCountry
+      op: eq
+      value: "225"
+`+"```").
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewBoolField("dry_run").
+				Description("Log the segment changes without applying them.").
+				Default(false),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}
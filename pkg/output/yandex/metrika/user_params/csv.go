@@ -0,0 +1,76 @@
+package user_params
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// encodeCSV converts the messages into the user parameters CSV file with a row per parameter.
+// Parameter values are omitted for the delete_keys action.
+func encodeCSV(contentIDType, action string, msgs service.MessageBatch) ([]byte, error) {
+	withValues := action != "delete_keys"
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	header := []string{contentIDType, "key"}
+	if withValues {
+		header = append(header, "value")
+	}
+
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, msg := range msgs {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return nil, err
+		}
+
+		row, ok := v.(map[string]any)
+		if !ok {
+			return nil, errors.New("user params message must be an object")
+		}
+
+		id := upload.FormatValue(row[contentIDType])
+		if id == "" {
+			return nil, fmt.Errorf("user params %s is required", contentIDType)
+		}
+
+		params, ok := row["params"].(map[string]any)
+		if !ok {
+			return nil, errors.New("user params message must have the params object")
+		}
+
+		// sorted keys make the file deterministic
+		keys := make([]string, 0, len(params))
+		for k := range params {
+			keys = append(keys, k)
+		}
+
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			record := []string{id, k}
+			if withValues {
+				record = append(record, upload.FormatValue(params[k]))
+			}
+
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,84 @@
+package user_params
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	testCases := []struct {
+		name          string
+		contentIDType string
+		action        string
+		rows          []any
+		expected      string
+		expectError   bool
+	}{
+		{
+			name:          "Sorted parameter keys",
+			contentIDType: "client_id",
+			action:        "update",
+			rows: []any{
+				map[string]any{"client_id": "123", "params": map[string]any{"tier": "gold", "age": 30.0, "active": true}},
+				map[string]any{"client_id": 456.0, "params": map[string]any{"tier": nil}},
+			},
+			expected: "client_id,key,value\n123,active,true\n123,age,30\n123,tier,gold\n456,tier,\n",
+		},
+		{
+			name:          "Escaping",
+			contentIDType: "user_id",
+			action:        "update",
+			rows: []any{
+				map[string]any{"user_id": "a,b", "params": map[string]any{"note": "say \"hi\"\nbye"}},
+			},
+			expected: "user_id,key,value\n\"a,b\",note,\"say \"\"hi\"\"\nbye\"\n",
+		},
+		{
+			name:          "Delete keys",
+			contentIDType: "client_id",
+			action:        "delete_keys",
+			rows: []any{
+				map[string]any{"client_id": "123", "params": map[string]any{"tier": "gold", "age": 30.0}},
+			},
+			expected: "client_id,key\n123,age\n123,tier\n",
+		},
+		{
+			name:          "Missing identifier of the type",
+			contentIDType: "user_id",
+			action:        "update",
+			rows:          []any{map[string]any{"client_id": "123", "params": map[string]any{"tier": "gold"}}},
+			expectError:   true,
+		},
+		{
+			name:          "Missing params",
+			contentIDType: "client_id",
+			action:        "update",
+			rows:          []any{map[string]any{"client_id": "123"}},
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs := make(service.MessageBatch, 0, len(tc.rows))
+
+			for _, row := range tc.rows {
+				msg := service.NewMessage(nil)
+				msg.SetStructured(row)
+				msgs = append(msgs, msg)
+			}
+
+			data, err := encodeCSV(tc.contentIDType, tc.action, msgs)
+			if tc.expectError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package user_params
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/artemklevtsov/redpanda-connect-plugins/pkg/input/yandex/metrika/api"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	apiKind    = "management"
+	apiVersion = "v1"
+)
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"yandex_metrika_user_params",
+		outputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchOutput, service.BatchPolicy, int, error) {
+			batchPolicy, err := conf.FieldBatchPolicy("batching")
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			maxInFlight, err := conf.FieldMaxInFlight()
+			if err != nil {
+				return nil, batchPolicy, 0, err
+			}
+
+			output, err := outputFromConfig(conf, mgr)
+
+			return output, batchPolicy, maxInFlight, err
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type benthosOutput struct {
+	token     string
+	query     *api.UserParamsQuery
+	writer    *upload.Writer
+	client    *api.Client
+	logger    *service.Logger
+	clientMut sync.Mutex
+}
+
+func (output *benthosOutput) Connect(ctx context.Context) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client != nil {
+		return nil
+	}
+
+	output.client = api.NewClient(
+		apiKind,
+		apiVersion,
+		output.token,
+		output.logger,
+	)
+
+	return nil
+}
+
+func (output *benthosOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	output.clientMut.Lock()
+	defer output.clientMut.Unlock()
+
+	if output.client == nil {
+		return service.ErrNotConnected
+	}
+
+	return upload.WriteBatch(ctx, output.writer, batch,
+		func(msgs service.MessageBatch) (struct{}, []byte, error) {
+			data, err := encodeCSV(output.query.ContentIDType, output.query.Action, msgs)
+
+			return struct{}{}, data, err
+		},
+		output.upload,
+	)
+}
+
+func (output *benthosOutput) Close(ctx context.Context) error {
+	return nil
+}
+
+// upload uploads and confirms the user parameters of the counter.
+// An uploading which is not confirmed is not applied, so it is uploaded again by a retry.
+func (output *benthosOutput) upload(ctx context.Context, counter int, msgs service.MessageBatch, _ struct{}, data []byte) (upload.Result, error) {
+	resp, err := output.client.UserParam.UploadWithContext(ctx, counter, output.query, data)
+	if err != nil {
+		return upload.Result{}, err
+	}
+
+	output.logger.
+		With("counter_id", counter, "uploading_id", resp.Uploading.Id, "action", output.query.Action, "rows", len(msgs)).
+		Info("user params are uploaded")
+
+	resp, err = output.client.UserParam.ConfirmWithContext(ctx, counter, resp.Uploading.Id, output.query)
+	if err != nil {
+		return upload.Result{}, err
+	}
+
+	output.logger.
+		With("counter_id", counter, "uploading_id", resp.Uploading.Id, "status", resp.Uploading.Status).
+		Info("user params uploading is confirmed")
+
+	return upload.Result{
+		ID:     strconv.FormatUint(resp.Uploading.Id, 10),
+		Status: resp.Uploading.Status,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package user_params
+
+import (
+	"github.com/artemklevtsov/redpanda-connect-plugins/internal/pkg/upload"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func outputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("api", "http", "yandex").
+		Summary("Creates an output that uploads user parameters to Yandex.Metrika API.").
+		Description(upload.Description(`Each batch of messages is uploaded as a CSV file per counter and confirmed. A message is an object with the fields:
+
+- `+"`client_id` or `user_id`"+`: the visitor identifier of the `+"`content_id_type`"+`;
+- `+"`params`"+`: an object of the parameter keys and values.
+
+The values are ignored by the `+"`delete_keys`"+` action.`)).
+		Fields(
+			service.NewStringField("token").
+				Description("Yandex.Metrika API token").
+				Secret().
+				Optional(),
+			service.NewInterpolatedStringField("counter_id").
+				Description("Yandex.Metrika Counter ID").
+				Example("44147844").
+				Example(`${! meta("counter_id") }`),
+			service.NewStringEnumField("content_id_type", "client_id", "user_id").
+				Description("Type of the visitor identifier.").
+				Default("client_id"),
+			service.NewStringAnnotatedEnumField("action", map[string]string{
+				"update":      "Create or update the parameters.",
+				"delete_keys": "Delete the parameter keys.",
+			}).
+				Description("Uploading action.").
+				Default("update"),
+			service.NewStringField("comment").
+				Description("Comment of the uploadings.").
+				Optional(),
+			upload.SyncResponseField(),
+			service.NewOutputMaxInFlightField().
+				Default(1),
+			service.NewBatchPolicyField("batching"),
+		)
+}